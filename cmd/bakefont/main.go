@@ -0,0 +1,102 @@
+// bakefont pregenerates youi SDF font atlases, so that applications don't have to
+// rasterize fonts at startup.
+//
+// Usage:
+//
+//	bakefont [-out dir] [-name name] [-chars ranges] [-size px] font.ttf [fallback.ttf...]
+//
+// The result (NAME.atlas.json and NAME.atlas*.png) goes in the "fonts" folder of an
+// application bundle, where font.LoadFont will prefer it over the TTF files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+
+	"github.com/hamcha/youi/font"
+)
+
+func main() {
+	out := flag.String("out", ".", "Output folder")
+	name := flag.String("name", "", "Font name (defaults to the TTF file names, separated by commas)")
+	chars := flag.String("chars", "20-7E,A0-FF", "Character ranges to include, as comma separated hex ranges (eg. 20-7E,3040-309F)")
+	size := flag.Int("size", font.DefaultTextureFontSize, "Font size used for the atlas, in pixels")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: bakefont [options] font.ttf [fallback.ttf...]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	ranges, err := parseRanges(*chars)
+	if err != nil {
+		fail(err)
+	}
+
+	// Load all fonts in the chain
+	var ttfs []*truetype.Font
+	var names []string
+	for _, path := range flag.Args() {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fail(err)
+		}
+		ttf, err := truetype.Parse(data)
+		if err != nil {
+			fail(fmt.Errorf("%s: %s", path, err.Error()))
+		}
+		ttfs = append(ttfs, ttf)
+		names = append(names, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	}
+	if *name == "" {
+		*name = strings.Join(names, font.FallbackSeparator)
+	}
+
+	fnt, err := font.MakeFontChain(ttfs, *size)
+	if err != nil {
+		fail(err)
+	}
+	for _, r := range ranges {
+		fnt.IncludeRange(r[0], r[1])
+	}
+
+	err = fnt.SaveBaked(*out, *name, names)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Printf("Baked %d glyphs in %d page(s) as \"%s\"\n", len(fnt.Atlas), len(fnt.Pages), *name)
+}
+
+// parseRanges parses a list of hex ranges like "20-7E,A0-FF,20AC"
+func parseRanges(str string) ([][2]rune, error) {
+	var ranges [][2]rune
+	for _, part := range strings.Split(str, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		from, err := strconv.ParseUint(bounds[0], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid character range \"%s\"", part)
+		}
+		to := from
+		if len(bounds) > 1 {
+			to, err = strconv.ParseUint(bounds[1], 16, 32)
+			if err != nil || to < from {
+				return nil, fmt.Errorf("invalid character range \"%s\"", part)
+			}
+		}
+		ranges = append(ranges, [2]rune{rune(from), rune(to)})
+	}
+	return ranges, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,498 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Binding errors
+var (
+	ErrInvalidBinding    = errors.New("bindings must look like {Binding Path, Mode=OneWay|TwoWay}")
+	ErrNotBindable       = errors.New("component does not support bindings")
+	ErrNotTwoWayBindable = errors.New("component attribute can't be bound both ways")
+)
+
+// BindingMode is which way values go between a binding's source and its component
+type BindingMode int
+
+// Binding modes
+const (
+	// BindOneWay updates the component attribute when the source changes
+	BindOneWay BindingMode = iota
+	// BindTwoWay also updates the source when the user changes the attribute
+	BindTwoWay
+)
+
+// AttributeSetter is implemented by components whose attributes can be changed after they
+// are made, which they need to be the target of bindings
+type AttributeSetter interface {
+	SetAttribute(name string, value Attribute) error
+}
+
+// AttributeGetter is implemented by components with attributes the user can change (such as
+// the text of a text box), which can be bound both ways. They send EventChange to themselves
+// when that happens.
+type AttributeGetter interface {
+	GetAttribute(name string) (Attribute, bool)
+}
+
+// ChangeNotifier is implemented by data contexts that tell bindings when their fields change
+type ChangeNotifier interface {
+	// Subscribe adds a function to call with the name of fields that change (empty for all
+	// of them), the returned function removes it
+	Subscribe(func(field string)) (unsubscribe func())
+}
+
+// Observable implements ChangeNotifier, data structs can embed it and call NotifyChange
+type Observable struct {
+	listeners []*func(string)
+}
+
+// Subscribe adds a function to call when a field changes
+func (o *Observable) Subscribe(fn func(field string)) func() {
+	listener := &fn
+	o.listeners = append(o.listeners, listener)
+	return func() {
+		for i, other := range o.listeners {
+			if other == listener {
+				o.listeners = append(o.listeners[:i], o.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// NotifyChange tells all subscribers that a field changed, empty means all fields did
+func (o *Observable) NotifyChange(field string) {
+	// Listeners may subscribe or unsubscribe while being called
+	listeners := append([]*func(string){}, o.listeners...)
+	for _, listener := range listeners {
+		(*listener)(field)
+	}
+}
+
+// Binding ties a component attribute to a value found from the component's data context
+type Binding struct {
+	// Attribute is the name of the bound attribute
+	Attribute string
+	// Path is a list of struct fields, map keys or methods (without arguments) separated by
+	// dots, empty means the data context itself
+	Path string
+	// Mode is which way values go
+	Mode BindingMode
+
+	component   Component
+	unsubscribe []func()
+	// updating is set while the component is being updated, writing while the source is
+	updating, writing bool
+	err               error
+}
+
+// ParseBinding parses an attribute value like {Binding User.Name, Mode=TwoWay}, ok is false
+// if the value is not a binding at all
+func ParseBinding(attribute string, value Attribute) (binding *Binding, ok bool, err error) {
+	str := strings.TrimSpace(value.String())
+	if !strings.HasPrefix(str, "{Binding") || !strings.HasSuffix(str, "}") {
+		return nil, false, nil
+	}
+	str = strings.TrimSuffix(strings.TrimPrefix(str, "{Binding"), "}")
+	if str != "" && str[0] != ' ' {
+		// Something like {BindingFoo}
+		return nil, false, nil
+	}
+
+	binding = &Binding{Attribute: attribute}
+	for i, part := range strings.Split(str, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if i == 0 {
+				continue
+			}
+			return nil, true, ErrInvalidBinding
+		}
+		key, val, named := strings.Cut(part, "=")
+		if !named {
+			// The path can be given without name, but only first
+			if i > 0 {
+				return nil, true, ErrInvalidBinding
+			}
+			key, val = "Path", part
+		}
+		switch strings.TrimSpace(key) {
+		case "Path":
+			binding.Path = strings.TrimSpace(val)
+		case "Mode":
+			switch strings.TrimSpace(val) {
+			case "OneWay":
+				binding.Mode = BindOneWay
+			case "TwoWay":
+				binding.Mode = BindTwoWay
+			default:
+				return nil, true, ErrInvalidBinding
+			}
+		default:
+			return nil, true, ErrInvalidBinding
+		}
+	}
+	return binding, true, nil
+}
+
+// ParseBindings takes the bindings out of a list of attributes, the returned list only has
+// the other ones
+func ParseBindings(list AttributeList) (AttributeList, []*Binding, error) {
+	var bindings []*Binding
+	rest := make(AttributeList)
+	for name, value := range list {
+		binding, ok, err := ParseBinding(name, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", name, err.Error())
+		}
+		if !ok {
+			rest[name] = value
+			continue
+		}
+		bindings = append(bindings, binding)
+	}
+	return rest, bindings, nil
+}
+
+// Bind adds a binding to a component, which is updated from its data context right away
+// (if there is one). Two-way bindings also update the source on EventChange.
+func Bind(c Component, binding *Binding) error {
+	if _, ok := c.(AttributeSetter); !ok {
+		return ErrNotBindable
+	}
+	if _, ok := c.(AttributeGetter); binding.Mode == BindTwoWay && !ok {
+		return ErrNotTwoWayBindable
+	}
+	binding.component = c
+	c.addBinding(binding)
+	if binding.Mode == BindTwoWay {
+		c.AddEventListener(EventChange, func(ev *Event) {
+			binding.write()
+		})
+	}
+	return binding.update()
+}
+
+// Err returns the error from the last time the binding was updated, if any
+func (b *Binding) Err() error {
+	return b.err
+}
+
+// update sets the attribute to the value in the source, and subscribes to the changes of
+// everything along the path
+func (b *Binding) update() error {
+	if b.writing {
+		// The source changed because of the component, it has the right value already
+		return nil
+	}
+	b.clearSubscriptions()
+
+	data := b.component.DataContext()
+	if data == nil {
+		// Nothing to bind to yet
+		b.err = nil
+		return nil
+	}
+
+	value, err := b.resolve(reflect.ValueOf(data), true)
+	if err == nil {
+		b.updating = true
+		err = b.component.(AttributeSetter).SetAttribute(b.Attribute, formatValue(value))
+		b.updating = false
+	}
+	if err != nil {
+		err = fmt.Errorf("binding %s to \"%s\": %s", b.Attribute, b.Path, err.Error())
+	}
+	b.err = err
+	return err
+}
+
+// write sets the value in the source to the attribute's
+func (b *Binding) write() {
+	if b.updating {
+		return
+	}
+	data := b.component.DataContext()
+	value, ok := b.component.(AttributeGetter).GetAttribute(b.Attribute)
+	if data == nil || !ok || b.Path == "" {
+		return
+	}
+
+	b.writing = true
+	defer func() { b.writing = false }()
+
+	// Find what holds the last field
+	fields := strings.Split(b.Path, ".")
+	parent := reflect.ValueOf(data)
+	if len(fields) > 1 {
+		var err error
+		parent, err = (&Binding{Path: strings.Join(fields[:len(fields)-1], ".")}).resolve(parent, false)
+		if err != nil {
+			b.err = err
+			return
+		}
+	}
+	field := fields[len(fields)-1]
+	if b.err = setField(parent, field, value.String()); b.err != nil {
+		return
+	}
+
+	// Let the other bindings know, if the source can tell them
+	if notifier, ok := pointerTo(parent).Interface().(interface{ NotifyChange(string) }); ok {
+		notifier.NotifyChange(field)
+	}
+}
+
+// resolve follows the path from the data context, subscribing to changes along the way
+func (b *Binding) resolve(value reflect.Value, subscribe bool) (reflect.Value, error) {
+	if b.Path == "" {
+		return value, nil
+	}
+	for _, field := range strings.Split(b.Path, ".") {
+		if subscribe {
+			b.subscribe(value, field)
+		}
+		next, err := getField(value, field)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value = next
+	}
+	return value, nil
+}
+
+// subscribe updates the binding when a field of a value changes, if the value tells
+func (b *Binding) subscribe(value reflect.Value, field string) {
+	value = pointerTo(value)
+	if !value.IsValid() || !value.CanInterface() {
+		return
+	}
+	notifier, ok := value.Interface().(ChangeNotifier)
+	if !ok {
+		return
+	}
+	b.unsubscribe = append(b.unsubscribe, notifier.Subscribe(func(changed string) {
+		if changed == "" || changed == field {
+			b.update()
+		}
+	}))
+}
+
+func (b *Binding) clearSubscriptions() {
+	for _, unsubscribe := range b.unsubscribe {
+		unsubscribe()
+	}
+	b.unsubscribe = nil
+}
+
+// getField returns a struct field, map value or method result by name
+func getField(value reflect.Value, name string) (reflect.Value, error) {
+	if !value.IsValid() {
+		return reflect.Value{}, fmt.Errorf("can't get %s of nil", name)
+	}
+
+	// Methods can be on pointers, so check before dereferencing
+	if method := pointerTo(value).MethodByName(name); method.IsValid() {
+		if method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			return reflect.Value{}, fmt.Errorf("method %s must take no arguments and return one value", name)
+		}
+		return method.Call(nil)[0], nil
+	}
+
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, fmt.Errorf("can't get %s of nil", name)
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if field := value.FieldByName(name); field.IsValid() {
+			return field, nil
+		}
+	case reflect.Map:
+		if value.Type().Key().Kind() == reflect.String {
+			if item := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key())); item.IsValid() {
+				return item, nil
+			}
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("%s has no field %s", value.Type(), name)
+}
+
+// pointerTo returns a pointer to a value if it can, so that methods with pointer receivers
+// (like the ones of Observable) can be found
+func pointerTo(value reflect.Value) reflect.Value {
+	if value.IsValid() && value.Kind() != reflect.Ptr && value.CanAddr() {
+		return value.Addr()
+	}
+	return value
+}
+
+// setField sets a struct field or map value by name, converting a string to its type
+func setField(value reflect.Value, name string, str string) error {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return fmt.Errorf("can't set %s of nil", name)
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		field := value.FieldByName(name)
+		if !field.IsValid() {
+			return fmt.Errorf("%s has no field %s", value.Type(), name)
+		}
+		if !field.CanSet() {
+			return fmt.Errorf("%s.%s can't be set (data contexts must be pointers)", value.Type(), name)
+		}
+		converted, err := parseValue(str, field.Type())
+		if err != nil {
+			return err
+		}
+		field.Set(converted)
+		return nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		converted, err := parseValue(str, value.Type().Elem())
+		if err != nil {
+			return err
+		}
+		value.SetMapIndex(reflect.ValueOf(name).Convert(value.Type().Key()), converted)
+		return nil
+	}
+	return fmt.Errorf("can't set %s of %s", name, value.Type())
+}
+
+// formatValue turns a source value into an attribute
+func formatValue(value reflect.Value) Attribute {
+	if !value.IsValid() {
+		return ""
+	}
+	if value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64 {
+		return Attribute(strconv.FormatFloat(value.Float(), 'g', -1, 64))
+	}
+	return Attribute(fmt.Sprint(value.Interface()))
+}
+
+// parseValue turns an attribute into a value of a given type
+func parseValue(str string, typ reflect.Type) (reflect.Value, error) {
+	value := reflect.New(typ).Elem()
+	var err error
+	switch typ.Kind() {
+	case reflect.String:
+		value.SetString(str)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(str)
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(str, 10, typ.Bits())
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(str, 10, typ.Bits())
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(str, typ.Bits())
+		value.SetFloat(f)
+	default:
+		return value, fmt.Errorf("can't convert \"%s\" to %s", str, typ)
+	}
+	if err != nil {
+		return value, fmt.Errorf("can't convert \"%s\" to %s", str, typ)
+	}
+	return value, nil
+}
+
+// SetDataContext sets what the bindings of the component and its children resolve against,
+// and updates them. Components without one use their parent's.
+func (c *Base) SetDataContext(data interface{}) error {
+	c.dataContext = data
+	c.hasDataContext = true
+	return c.updateBindings()
+}
+
+// ClearDataContext makes the component use its parent's data context again
+func (c *Base) ClearDataContext() error {
+	c.dataContext = nil
+	c.hasDataContext = false
+	return c.updateBindings()
+}
+
+// DataContext returns what the component's bindings resolve against
+func (c *Base) DataContext() interface{} {
+	if c.hasDataContext {
+		return c.dataContext
+	}
+	if c.parent != nil {
+		return c.parent.DataContext()
+	}
+	return nil
+}
+
+// Bindings returns the bindings added to the component
+func (c *Base) Bindings() []*Binding {
+	return c.bindings
+}
+
+func (c *Base) addBinding(binding *Binding) {
+	c.bindings = append(c.bindings, binding)
+}
+
+// updateBindings updates the bindings of the component and all its children, returning the
+// first error
+func (c *Base) updateBindings() error {
+	var first error
+	for _, binding := range c.bindings {
+		if err := binding.update(); err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, child := range c.children {
+		if err := child.updateBindings(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// UpdateBindings updates all bindings in a tree, eg. after it's added to a parent with a
+// different data context
+func UpdateBindings(root Component) error {
+	return root.updateBindings()
+}
+
+// HasBindings returns whether there are bindings anywhere in a tree
+func HasBindings(root Component) bool {
+	if len(root.Bindings()) > 0 {
+		return true
+	}
+	for _, child := range root.Children() {
+		if HasBindings(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unbind stops all bindings in a tree from following changes of their sources, call it on
+// trees that are thrown away (or put aside) so their sources don't keep them around. Setting
+// a data context or updating the bindings again brings them back.
+func Unbind(root Component) {
+	for _, binding := range root.Bindings() {
+		binding.clearSubscriptions()
+	}
+	for _, child := range root.Children() {
+		Unbind(child)
+	}
+}
@@ -0,0 +1,138 @@
+package components
+
+import "testing"
+
+// field is a component with a single attribute the user can change
+type field struct {
+	Base
+	value Attribute
+}
+
+func (f *field) SetAttribute(name string, value Attribute) error {
+	f.value = value
+	return nil
+}
+
+func (f *field) GetAttribute(name string) (Attribute, bool) {
+	return f.value, true
+}
+
+// edit changes the value as the user would
+func (f *field) edit(value Attribute) {
+	f.value = value
+	f.HandleEvent(&Event{Type: EventChange, Phase: PhaseTarget})
+}
+
+type bindingUser struct {
+	Observable
+	Name string
+	Age  int
+}
+
+type bindingModel struct {
+	Observable
+	User *bindingUser
+}
+
+func TestParseBinding(t *testing.T) {
+	tests := []struct {
+		value string
+		path  string
+		mode  BindingMode
+		ok    bool
+		err   bool
+	}{
+		{"{Binding}", "", BindOneWay, true, false},
+		{"{Binding User.Name}", "User.Name", BindOneWay, true, false},
+		{"{Binding Path=User.Name, Mode=TwoWay}", "User.Name", BindTwoWay, true, false},
+		{"{Binding User, Mode=Sideways}", "", BindOneWay, true, true},
+		{"{Binding Mode=TwoWay, User}", "", BindOneWay, true, true},
+		{"{Bindings}", "", BindOneWay, false, false},
+		{"plain text", "", BindOneWay, false, false},
+	}
+	for _, test := range tests {
+		binding, ok, err := ParseBinding("Text", Attribute(test.value))
+		if ok != test.ok || (err != nil) != test.err {
+			t.Errorf("%s: expected ok=%t err=%t, got ok=%t err=%v", test.value, test.ok, test.err, ok, err)
+			continue
+		}
+		if binding != nil && (binding.Path != test.path || binding.Mode != test.mode) {
+			t.Errorf("%s: expected path %q mode %d, got %q mode %d", test.value, test.path, test.mode, binding.Path, binding.Mode)
+		}
+	}
+}
+
+func TestBindingUpdates(t *testing.T) {
+	root := &Base{}
+	name, age := &field{}, &field{}
+	root.AppendChild(name)
+	root.AppendChild(age)
+	if err := Bind(name, &Binding{Attribute: "Text", Path: "User.Name", Mode: BindTwoWay}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Bind(age, &Binding{Attribute: "Text", Path: "User.Age", Mode: BindTwoWay}); err != nil {
+		t.Fatal(err)
+	}
+
+	model := &bindingModel{User: &bindingUser{Name: "Ann", Age: 30}}
+	if err := root.SetDataContext(model); err != nil {
+		t.Fatal(err)
+	}
+	if name.value != "Ann" || age.value != "30" {
+		t.Fatalf("expected initial values, got %q and %q", name.value, age.value)
+	}
+
+	// Changes anywhere along the path are picked up
+	model.User.Name = "Bob"
+	model.User.NotifyChange("Name")
+	if name.value != "Bob" {
+		t.Fatalf("expected name to follow the source, got %q", name.value)
+	}
+	model.User = &bindingUser{Name: "Carl"}
+	model.NotifyChange("User")
+	if name.value != "Carl" {
+		t.Fatalf("expected name to follow the new user, got %q", name.value)
+	}
+
+	// Two-way bindings write back, converting to the field type
+	age.edit("42")
+	if model.User.Age != 42 {
+		t.Fatalf("expected age to be written back, got %d", model.User.Age)
+	}
+	age.edit("old")
+	if model.User.Age != 42 || age.Bindings()[0].Err() == nil {
+		t.Fatal("expected invalid values to be rejected")
+	}
+}
+
+func TestUnbind(t *testing.T) {
+	root := &Base{}
+	name := &field{}
+	root.AppendChild(name)
+	if err := Bind(name, &Binding{Attribute: "Text", Path: "User.Name"}); err != nil {
+		t.Fatal(err)
+	}
+	model := &bindingModel{User: &bindingUser{Name: "Ann"}}
+	if err := name.SetDataContext(model); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bindings of the whole tree stop listening, even with their own data context
+	Unbind(root)
+	if len(model.listeners) != 0 || len(model.User.listeners) != 0 {
+		t.Fatalf("expected no listeners left, got %d and %d", len(model.listeners), len(model.User.listeners))
+	}
+	model.User.Name = "Bob"
+	model.User.NotifyChange("Name")
+	if name.value != "Ann" {
+		t.Fatalf("expected unbound component to keep its value, got %q", name.value)
+	}
+
+	// Updating brings them back
+	if err := UpdateBindings(root); err != nil {
+		t.Fatal(err)
+	}
+	if name.value != "Bob" {
+		t.Fatalf("expected name to follow the source again, got %q", name.value)
+	}
+}
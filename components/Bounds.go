@@ -3,6 +3,8 @@ package components
 import (
 	"fmt"
 	"image"
+
+	"github.com/hamcha/youi/render"
 )
 
 type Position struct {
@@ -38,6 +40,36 @@ type Bounds struct {
 	Size
 }
 
+// Insets are spaces around the four sides of a rectangle (eg. padding)
+type Insets struct {
+	Left, Top, Right, Bottom float32
+}
+
+func (i Insets) String() string {
+	if i.Left == i.Right && i.Top == i.Bottom {
+		if i.Left == i.Top {
+			return fmt.Sprintf("%g", i.Left)
+		}
+		return fmt.Sprintf("%g,%g", i.Left, i.Top)
+	}
+	return fmt.Sprintf("%g,%g,%g,%g", i.Left, i.Top, i.Right, i.Bottom)
+}
+
+// Shrink returns the bounds with the insets removed from each side
+func (b Bounds) Shrink(i Insets) Bounds {
+	out := Bounds{
+		Position{b.X + i.Left, b.Y + i.Top},
+		Size{b.Width - i.Left - i.Right, b.Height - i.Top - i.Bottom},
+	}
+	if out.Width < 0 {
+		out.Width = 0
+	}
+	if out.Height < 0 {
+		out.Height = 0
+	}
+	return out
+}
+
 func BoundsFromRect(rect image.Rectangle) Bounds {
 	size := rect.Size()
 	return Bounds{
@@ -59,6 +91,22 @@ func (b Bounds) Scale(s Size) Bounds {
 	}
 }
 
+// Rect converts the bounds to a renderer rectangle
+func (b Bounds) Rect() render.Rect {
+	return render.Rect{
+		X:      b.X,
+		Y:      b.Y,
+		Width:  b.Width,
+		Height: b.Height,
+	}
+}
+
+// Contains returns whether a position falls inside the bounds
+func (b Bounds) Contains(p Position) bool {
+	return p.X >= b.X && p.X < b.X+b.Width &&
+		p.Y >= b.Y && p.Y < b.Y+b.Height
+}
+
 func (b Bounds) String() string {
 	return fmt.Sprintf("Position %s Size %s", b.Position, b.Size)
 }
@@ -0,0 +1,63 @@
+package components
+
+import (
+	"errors"
+
+	"github.com/hamcha/youi/render"
+)
+
+// SetClipChildren sets whether children are only drawn (and hit) inside the component
+func (c *Base) SetClipChildren(clip bool) {
+	c.clipChildren = clip
+	c.SetRedraw()
+}
+
+// ClipsChildren returns whether children are only drawn inside the component
+func (c *Base) ClipsChildren() bool {
+	return c.clipChildren
+}
+
+// SetClipRadius rounds the corners of the clipping area by a radius in pixels, it has no
+// effect unless children are clipped
+func (c *Base) SetClipRadius(radius float32) {
+	c.clipRadius = radius
+	c.SetRedraw()
+}
+
+// ClipRadius returns the radius of the clipping area corners, in pixels
+func (c *Base) ClipRadius() float32 {
+	return c.clipRadius
+}
+
+// pushClip restricts drawing to the component bounds, until the renderer's PopClip
+func (c *Base) pushClip(r render.Renderer) {
+	rect := render.Rect{Width: 1, Height: 1}
+	if !c.isRoot() {
+		rect = c.bounds.Rect()
+	}
+	if c.clipRadius > 0 {
+		r.PushRoundedClip(rect, c.clipRadius)
+	} else {
+		r.PushClip(rect)
+	}
+}
+
+// ParseClip reads the ClipChildren and ClipRadius attributes, which are supported by every
+// component, and applies them
+func ParseClip(c Component, list AttributeList) error {
+	if attr, ok := list["ClipChildren"]; ok {
+		clip, err := attr.Bool()
+		if err != nil {
+			return errors.New("ClipChildren must be either true or false")
+		}
+		c.SetClipChildren(clip)
+	}
+	if attr, ok := list["ClipRadius"]; ok {
+		radius, err := attr.Float32()
+		if err != nil || radius < 0 {
+			return errors.New("ClipRadius must be a positive number")
+		}
+		c.SetClipRadius(radius)
+	}
+	return nil
+}
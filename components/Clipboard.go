@@ -0,0 +1,34 @@
+package components
+
+// Clipboard is where text goes when copied and comes from when pasted
+type Clipboard interface {
+	ClipboardText() string
+	SetClipboardText(string)
+}
+
+// memoryClipboard is a clipboard that only lives inside the program, used when there is no
+// system clipboard to talk to (eg. offscreen forms)
+type memoryClipboard struct {
+	text string
+}
+
+func (m *memoryClipboard) ClipboardText() string {
+	return m.text
+}
+
+func (m *memoryClipboard) SetClipboardText(str string) {
+	m.text = str
+}
+
+var clipboard Clipboard = new(memoryClipboard)
+
+// SetClipboard sets the clipboard used by text components, forms on a window set it to
+// the system clipboard
+func SetClipboard(c Clipboard) {
+	clipboard = c
+}
+
+// GetClipboard returns the clipboard used by text components
+func GetClipboard() Clipboard {
+	return clipboard
+}
@@ -3,17 +3,23 @@ package components
 import (
 	"fmt"
 
+	"github.com/hamcha/youi/render"
 	"github.com/hamcha/youi/utils"
 )
 
 // Component is a renderable UI component that can optionally hold children
 type Component interface {
-	Draw()
+	Draw(render.Renderer)
 	ShouldDraw() bool
 
 	Bounds() Bounds
 	SetBounds(Bounds)
 
+	Measure(available Size) Size
+	Arrange(pixels Bounds)
+	SetConstraints(min, max Size)
+	Constraints() (min, max Size)
+
 	Parent() Component
 	Children() ComponentList
 	Root() Component
@@ -25,18 +31,55 @@ type Component interface {
 	FindChildIndex(Component) int
 	RemoveChildByIndex(int) error
 
+	AddEventListener(EventType, EventListener)
+	AddCaptureListener(EventType, EventListener)
+	HandleEvent(*Event)
+
+	SetID(string)
+	ID() string
+
+	SetFocusable(bool)
+	SetTabIndex(int)
+	TabIndex() int
+
+	SetClipChildren(bool)
+	ClipsChildren() bool
+	SetClipRadius(float32)
+
+	SetDataContext(interface{}) error
+	DataContext() interface{}
+	Bindings() []*Binding
+
 	setParent(Component)
+	focusMode() focusMode
+	addBinding(*Binding)
+	updateBindings() error
 }
 
 // Base is the common parent of all components
 type Base struct {
 	parent Component
+	id     string
 
 	bounds      Bounds
 	dirtyBounds bool
 
+	minSize, maxSize Size
+
 	children      ComponentList
 	dirtyChildren bool
+
+	listeners []eventListener
+
+	focus    focusMode
+	tabIndex int
+
+	clipChildren bool
+	clipRadius   float32
+
+	dataContext    interface{}
+	hasDataContext bool
+	bindings       []*Binding
 }
 
 // ComponentList is a modifiable, ordered list of components
@@ -59,8 +102,8 @@ func (c *Base) Bounds() Bounds {
 	return c.bounds
 }
 
-func (c *Base) Draw() {
-	c.drawChildren()
+func (c *Base) Draw(r render.Renderer) {
+	c.drawChildren(r)
 	c.ClearFlags()
 }
 
@@ -87,14 +130,31 @@ func (c *Base) Children() ComponentList {
 	return c.children
 }
 
-func (c *Base) drawChildren() {
+func (c *Base) drawChildren(r render.Renderer) {
+	if c.clipChildren && len(c.children) > 0 {
+		c.pushClip(r)
+		defer r.PopClip()
+	}
 	for _, child := range c.children {
 		//if child.ShouldDraw() {
-		child.Draw()
+		child.Draw(r)
 		//}
 	}
 }
 
+// PixelBounds returns the component bounds in pixels, using the root size as reference
+func (c *Base) PixelBounds() Bounds {
+	if c.isRoot() {
+		return c.bounds
+	}
+	return c.bounds.Scale(c.Root().Bounds().Size)
+}
+
+// ToRelative converts pixel bounds to bounds relative to the root, as used by SetBounds
+func (c *Base) ToRelative(pixels Bounds) Bounds {
+	return pixels.Scale(c.Root().Bounds().Size.Inverse())
+}
+
 func (c *Base) Root() Component {
 	if c.parent == nil {
 		return c
@@ -167,3 +227,20 @@ func (c *Base) ChildrenStr() (out string) {
 	}
 	return
 }
+
+// Destroyer is implemented by components that hold resources of a renderer
+type Destroyer interface {
+	// Destroy frees the resources, they are made again if the component is drawn
+	Destroy()
+}
+
+// Destroy frees the renderer resources held by all components in a tree, call it on trees
+// that won't be drawn anymore
+func Destroy(root Component) {
+	if destroyer, ok := root.(Destroyer); ok {
+		destroyer.Destroy()
+	}
+	for _, child := range root.Children() {
+		Destroy(child)
+	}
+}
@@ -0,0 +1,160 @@
+package components
+
+import (
+	"image/color"
+
+	"github.com/hamcha/youi/render"
+)
+
+// VisualState is how an interactive component should look, depending on the input it's getting
+type VisualState int
+
+// Visual states, in order of priority (a disabled control never looks hovered or pressed)
+const (
+	StateNormal VisualState = iota
+	StateHovered
+	StatePressed
+	StateDisabled
+)
+
+// StateColors holds one color per visual state, nil colors fall back to Normal
+type StateColors struct {
+	Normal, Hovered, Pressed, Disabled color.Color
+}
+
+// Get returns the color for a visual state
+func (s StateColors) Get(state VisualState) color.Color {
+	var col color.Color
+	switch state {
+	case StateHovered:
+		col = s.Hovered
+	case StatePressed:
+		col = s.Pressed
+	case StateDisabled:
+		col = s.Disabled
+	}
+	if col == nil {
+		return s.Normal
+	}
+	return col
+}
+
+// ControlState keeps track of the state of an interactive component (buttons, sliders, etc.)
+// from the input events it receives. Components embed it and call BindEvents on themselves.
+type ControlState struct {
+	hovered, pressed, focused, disabled bool
+	dirty                               bool
+
+	// focusRing is the color of the focus ring, used instead of DefaultFocusRingColor once
+	// hasFocusRing is set
+	focusRing    color.Color
+	hasFocusRing bool
+}
+
+// BindEvents adds the listeners that keep the state updated to a component
+func (s *ControlState) BindEvents(c Component) {
+	c.AddEventListener(EventMouseEnter, func(ev *Event) {
+		s.set(&s.hovered, true)
+	})
+	c.AddEventListener(EventMouseLeave, func(ev *Event) {
+		s.set(&s.hovered, false)
+	})
+	c.AddEventListener(EventMouseDown, func(ev *Event) {
+		if !s.disabled {
+			s.set(&s.pressed, true)
+		}
+	})
+	c.AddEventListener(EventMouseUp, func(ev *Event) {
+		s.set(&s.pressed, false)
+	})
+}
+
+func (s *ControlState) set(field *bool, value bool) {
+	if *field != value {
+		*field = value
+		s.dirty = true
+	}
+}
+
+// VisualState returns how the component should look
+func (s *ControlState) VisualState() VisualState {
+	switch {
+	case s.disabled:
+		return StateDisabled
+	case s.pressed:
+		return StatePressed
+	case s.hovered:
+		return StateHovered
+	}
+	return StateNormal
+}
+
+// Hovered returns whether the cursor is over the component
+func (s *ControlState) Hovered() bool {
+	return s.hovered
+}
+
+// Pressed returns whether a mouse button is being held on the component
+func (s *ControlState) Pressed() bool {
+	return s.pressed
+}
+
+// SetFocused sets whether the component has keyboard focus, disabled components can't
+func (s *ControlState) SetFocused(focused bool) {
+	s.set(&s.focused, focused && !s.disabled)
+}
+
+// Focused returns whether the component has keyboard focus
+func (s *ControlState) Focused() bool {
+	return s.focused
+}
+
+// SetFocusRingColor sets the color of the ring drawn around the component while it's
+// focused, nil means no ring. Forms set it to their FocusRingColor.
+func (s *ControlState) SetFocusRingColor(col color.Color) {
+	if s.hasFocusRing && s.focusRing == col {
+		return
+	}
+	s.focusRing, s.hasFocusRing = col, true
+	s.dirty = true
+}
+
+// FocusRingColor returns the color of the focus ring, DefaultFocusRingColor unless
+// SetFocusRingColor was called
+func (s *ControlState) FocusRingColor() color.Color {
+	if !s.hasFocusRing {
+		return DefaultFocusRingColor
+	}
+	return s.focusRing
+}
+
+// DrawFocusRing draws the focus ring around a rectangle, if the component is focused
+func (s *ControlState) DrawFocusRing(r render.Renderer, rect render.Rect) {
+	if col := s.FocusRingColor(); s.focused && col != nil {
+		render.DrawOutline(r, rect, col, FocusRingWidth)
+	}
+}
+
+// SetDisabled sets whether the component ignores input
+func (s *ControlState) SetDisabled(disabled bool) {
+	s.set(&s.disabled, disabled)
+	if disabled {
+		s.pressed = false
+		s.focused = false
+	}
+}
+
+// Disabled returns whether the component ignores input
+func (s *ControlState) Disabled() bool {
+	return s.disabled
+}
+
+// StateChanged returns whether the state changed since the last ClearState
+func (s *ControlState) StateChanged() bool {
+	return s.dirty
+}
+
+// ClearState resets the StateChanged flag, after drawing
+func (s *ControlState) ClearState() {
+	s.dirty = false
+}
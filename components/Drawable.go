@@ -2,29 +2,28 @@ package components
 
 import (
 	"errors"
+	"image/color"
 
-	"github.com/hamcha/youi/opengl"
+	"github.com/hamcha/youi/render"
 )
 
+// Drawable is a component that fills its bounds with a background color and/or a texture
 type Drawable struct {
 	Base
-	Mesh *opengl.Mesh
+	Background color.Color
+	Texture    render.Texture
 }
 
-func (c *Drawable) Draw() {
-	if c.Mesh == nil {
-		c.Mesh = opengl.MakeQuad(opengl.DefaultShader())
+func (c *Drawable) Draw(r render.Renderer) {
+	rect := c.bounds.Rect()
+	if c.Background != nil {
+		r.FillRect(rect, c.Background)
 	}
-
-	// Check if bounds have changed
-	if c.dirtyBounds {
-		// Update transform matrix
-		mat := getTransformMatrix(c.bounds)
-		c.Mesh.Shader.GetUniform("transform").Set(mat)
+	if c.Texture != nil {
+		r.DrawTexture(c.Texture, rect)
 	}
 
-	c.Mesh.Draw()
-	c.Base.Draw()
+	c.Base.Draw(r)
 }
 
 // Component handling errors
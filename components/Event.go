@@ -0,0 +1,230 @@
+package components
+
+import "github.com/hamcha/youi/input"
+
+// EventType identifies what kind of input an event carries
+type EventType int
+
+// Event types
+const (
+	EventMouseMove EventType = iota
+	EventMouseDown
+	EventMouseUp
+	EventClick
+	EventScroll
+	EventKeyDown
+	EventKeyUp
+	EventChar
+	// EventMouseEnter and EventMouseLeave are sent when the cursor starts or stops being
+	// over a component (only the topmost one counts)
+	EventMouseEnter
+	EventMouseLeave
+	// EventFocus and EventBlur are sent when a component gets or loses keyboard focus
+	EventFocus
+	EventBlur
+	// EventChange is sent by controls to themselves when the user changes their value
+	EventChange
+)
+
+var eventNames = map[EventType]string{
+	EventMouseMove:  "MouseMove",
+	EventMouseDown:  "MouseDown",
+	EventMouseUp:    "MouseUp",
+	EventClick:      "Click",
+	EventScroll:     "Scroll",
+	EventKeyDown:    "KeyDown",
+	EventKeyUp:      "KeyUp",
+	EventChar:       "Char",
+	EventMouseEnter: "MouseEnter",
+	EventMouseLeave: "MouseLeave",
+	EventFocus:      "Focus",
+	EventBlur:       "Blur",
+	EventChange:     "Change",
+}
+
+func (t EventType) String() string {
+	name, ok := eventNames[t]
+	if !ok {
+		return "Unknown"
+	}
+	return name
+}
+
+// ParseEventType returns the event type with a name (eg. Click), as used in YUML event
+// attributes like OnClick
+func ParseEventType(name string) (EventType, bool) {
+	for eventType, eventName := range eventNames {
+		if eventName == name {
+			return eventType, true
+		}
+	}
+	return 0, false
+}
+
+// EventPhase is the step of the dispatch an event is currently going through
+type EventPhase int
+
+// Event phases
+const (
+	// PhaseCapture is when the event travels from the root down to the target's parent
+	PhaseCapture EventPhase = iota
+	// PhaseTarget is when the event is being handled by its target
+	PhaseTarget
+	// PhaseBubble is when the event travels back from the target's parent up to the root
+	PhaseBubble
+)
+
+// Event is a single input event being dispatched through the component tree
+type Event struct {
+	Type  EventType
+	Phase EventPhase
+
+	// Target is the component the event was originally sent to
+	Target Component
+	// CurrentTarget is the component whose listeners are being called
+	CurrentTarget Component
+
+	// Position is the cursor position, relative to the root (same units as Bounds)
+	Position Position
+	// ScrollX and ScrollY are the scroll offsets for EventScroll
+	ScrollX, ScrollY float32
+
+	Button    input.MouseButton
+	Key       input.Key
+	Modifiers input.Modifier
+	Repeat    bool
+	Char      rune
+
+	stopped bool
+}
+
+// StopPropagation prevents the event from reaching any other component after the current one
+func (e *Event) StopPropagation() {
+	e.stopped = true
+}
+
+// Stopped returns whether StopPropagation was called on the event
+func (e *Event) Stopped() bool {
+	return e.stopped
+}
+
+// EventListener is a function that gets called when a component receives an event
+type EventListener func(*Event)
+
+type eventListener struct {
+	eventType EventType
+	fn        EventListener
+	capture   bool
+}
+
+// AddEventListener adds a listener that will be called during the target and bubble phases
+func (c *Base) AddEventListener(eventType EventType, fn EventListener) {
+	c.listeners = append(c.listeners, eventListener{eventType, fn, false})
+}
+
+// AddCaptureListener adds a listener that will be called during the capture and target phases
+func (c *Base) AddCaptureListener(eventType EventType, fn EventListener) {
+	c.listeners = append(c.listeners, eventListener{eventType, fn, true})
+}
+
+// HandleEvent calls all the listeners registered for the event's type and phase
+func (c *Base) HandleEvent(ev *Event) {
+	for _, listener := range c.listeners {
+		if listener.eventType != ev.Type {
+			continue
+		}
+		switch ev.Phase {
+		case PhaseCapture:
+			if !listener.capture {
+				continue
+			}
+		case PhaseBubble:
+			if listener.capture {
+				continue
+			}
+		}
+		listener.fn(ev)
+	}
+}
+
+// HitTest returns the topmost component under a position (relative to the root).
+// Children are checked before their parents, and later children before earlier ones
+// since they are drawn on top. The root always counts as hit.
+func HitTest(root Component, pos Position) Component {
+	if hit := hitTestChildren(root, pos); hit != nil {
+		return hit
+	}
+	return root
+}
+
+func hitTestChildren(parent Component, pos Position) Component {
+	// Children of clipping containers can't be hit outside of them
+	if parent.ClipsChildren() && parent.Parent() != nil && !parent.Bounds().Contains(pos) {
+		return nil
+	}
+	children := parent.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		child := children[i]
+		if hit := hitTestChildren(child, pos); hit != nil {
+			return hit
+		}
+		if child.Bounds().Contains(pos) {
+			return child
+		}
+	}
+	return nil
+}
+
+// PathTo returns all the components from root down to target (both included), or nil if target
+// is not in root's tree. The path is built from the top since it needs the actual components
+// and not their embedded Base.
+func PathTo(root, target Component) ComponentList {
+	if root == target {
+		return ComponentList{root}
+	}
+	for _, child := range root.Children() {
+		if path := PathTo(child, target); path != nil {
+			return append(ComponentList{root}, path...)
+		}
+	}
+	return nil
+}
+
+// DispatchEvent sends an event to a target inside root's tree, going through the capture phase
+// (root to parent), the target itself and the bubble phase (parent to root), unless propagation
+// is stopped.
+func DispatchEvent(root, target Component, ev *Event) {
+	path := PathTo(root, target)
+	if path == nil {
+		return
+	}
+	ev.Target = target
+
+	// Capture phase
+	ev.Phase = PhaseCapture
+	for _, cmp := range path[:len(path)-1] {
+		ev.CurrentTarget = cmp
+		cmp.HandleEvent(ev)
+		if ev.stopped {
+			return
+		}
+	}
+
+	// Target phase
+	ev.Phase = PhaseTarget
+	ev.CurrentTarget = target
+	target.HandleEvent(ev)
+	if ev.stopped {
+		return
+	}
+
+	// Bubble phase
+	ev.Phase = PhaseBubble
+	for i := len(path) - 2; i >= 0; i-- {
+		ev.CurrentTarget = path[i]
+		path[i].HandleEvent(ev)
+		if ev.stopped {
+			return
+		}
+	}
+}
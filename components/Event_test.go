@@ -0,0 +1,76 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDispatchEventPhases(t *testing.T) {
+	root := &Base{}
+	parent := &Base{}
+	child := &Base{}
+	root.AppendChild(parent)
+	parent.AppendChild(child)
+
+	var got []string
+	logger := func(name string) EventListener {
+		return func(ev *Event) {
+			got = append(got, name)
+		}
+	}
+	root.AddCaptureListener(EventClick, logger("root-capture"))
+	root.AddEventListener(EventClick, logger("root-bubble"))
+	parent.AddCaptureListener(EventClick, logger("parent-capture"))
+	parent.AddEventListener(EventClick, logger("parent-bubble"))
+	child.AddEventListener(EventClick, logger("child"))
+	child.AddEventListener(EventMouseDown, logger("child-mousedown"))
+
+	DispatchEvent(root, child, &Event{Type: EventClick})
+
+	expected := []string{"root-capture", "parent-capture", "child", "parent-bubble", "root-bubble"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong listener order, expected %v, got %v", expected, got)
+	}
+}
+
+func TestDispatchEventStopPropagation(t *testing.T) {
+	root := &Base{}
+	child := &Base{}
+	root.AppendChild(child)
+
+	rootCalled := false
+	root.AddEventListener(EventKeyDown, func(ev *Event) { rootCalled = true })
+	child.AddEventListener(EventKeyDown, func(ev *Event) { ev.StopPropagation() })
+
+	ev := &Event{Type: EventKeyDown}
+	DispatchEvent(root, child, ev)
+
+	if rootCalled {
+		t.Error("event reached root after StopPropagation")
+	}
+	if !ev.Stopped() {
+		t.Error("event should be marked as stopped")
+	}
+}
+
+func TestHitTest(t *testing.T) {
+	root := &Base{}
+	bottom := &Base{}
+	top := &Base{}
+	root.AppendChild(bottom)
+	root.AppendChild(top)
+	bottom.SetBounds(Bounds{Position{0, 0}, Size{1, 1}})
+	top.SetBounds(Bounds{Position{0.5, 0.5}, Size{0.5, 0.5}})
+
+	if hit := HitTest(root, Position{0.75, 0.75}); hit != top {
+		t.Error("expected topmost child to be hit")
+	}
+	if hit := HitTest(root, Position{0.25, 0.25}); hit != bottom {
+		t.Error("expected bottom child to be hit")
+	}
+
+	outside := HitTest(bottom, Position{2, 2})
+	if outside != bottom {
+		t.Error("root should be hit when no child contains the position")
+	}
+}
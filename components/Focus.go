@@ -0,0 +1,125 @@
+package components
+
+import (
+	"errors"
+	"image/color"
+	"sort"
+)
+
+// DefaultFocusRingColor is the color of the ring around focused components, unless the
+// form they're in uses another one
+var DefaultFocusRingColor color.Color = color.RGBA{0x66, 0x99, 0xff, 0xff}
+
+// FocusRingWidth is how thick focus rings are, in pixels
+const FocusRingWidth = 2
+
+// Focusable is implemented by components that take keyboard input and show whether they
+// have focus themselves. They can get focus by default, see CanFocus.
+type Focusable interface {
+	SetFocused(bool)
+}
+
+// FocusRingDrawer is implemented by Focusable components that draw their own focus ring,
+// so that the form can tell them which color to use (nil for no ring)
+type FocusRingDrawer interface {
+	SetFocusRingColor(color.Color)
+}
+
+// focusMode is whether a component was explicitly made focusable or not
+type focusMode int
+
+const (
+	focusDefault focusMode = iota
+	focusOn
+	focusOff
+)
+
+// SetFocusable sets whether the component can get keyboard focus, overriding the default
+// (see CanFocus)
+func (c *Base) SetFocusable(focusable bool) {
+	c.focus = focusOff
+	if focusable {
+		c.focus = focusOn
+	}
+}
+
+func (c *Base) focusMode() focusMode {
+	return c.focus
+}
+
+// SetTabIndex sets where the component is in the tab order: components with a positive
+// index come first (lowest first), then the ones with zero in tree order. Components with
+// a negative index can only be focused by clicking them.
+func (c *Base) SetTabIndex(index int) {
+	c.tabIndex = index
+}
+
+// TabIndex returns where the component is in the tab order
+func (c *Base) TabIndex() int {
+	return c.tabIndex
+}
+
+// CanFocus returns whether a component can get keyboard focus: components implementing
+// Focusable can unless they are disabled, others only if SetFocusable(true) was called
+func CanFocus(c Component) bool {
+	switch c.focusMode() {
+	case focusOn:
+		return true
+	case focusOff:
+		return false
+	}
+	if disabled, ok := c.(interface{ Disabled() bool }); ok && disabled.Disabled() {
+		return false
+	}
+	_, ok := c.(Focusable)
+	return ok
+}
+
+// FocusOrder returns the components in root's tree that can be reached with the tab key, in
+// the order they are reached
+func FocusOrder(root Component) ComponentList {
+	var list ComponentList
+	var walk func(Component)
+	walk = func(c Component) {
+		if CanFocus(c) && c.TabIndex() >= 0 {
+			list = append(list, c)
+		}
+		for _, child := range c.Children() {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	// Positive indexes first, ties (and zeroes) keep tree order
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i].TabIndex(), list[j].TabIndex()
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+	return list
+}
+
+// ErrInvalidTabIndex means the TabIndex attribute is not an integer
+var ErrInvalidTabIndex = errors.New("TabIndex must be an integer")
+
+// ParseFocus reads the Focusable and TabIndex attributes, which are supported by every
+// component, and applies them
+func ParseFocus(c Component, list AttributeList) error {
+	if attr, ok := list["Focusable"]; ok {
+		focusable, err := attr.Bool()
+		if err != nil {
+			return errors.New("Focusable must be either true or false")
+		}
+		c.SetFocusable(focusable)
+	}
+	if attr, ok := list["TabIndex"]; ok {
+		index, err := attr.Int()
+		if err != nil {
+			return ErrInvalidTabIndex
+		}
+		c.SetTabIndex(index)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package components
+
+import "testing"
+
+// focusable is a component that takes focus by default
+type focusable struct {
+	Base
+	focused bool
+}
+
+func (f *focusable) SetFocused(focused bool) {
+	f.focused = focused
+}
+
+// sameComponents compares lists by identity, since different components can look the same
+func sameComponents(a, b ComponentList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFocusOrder(t *testing.T) {
+	root := &Base{}
+	first, second, third := &focusable{}, &focusable{}, &focusable{}
+	plain, skipped := &Base{}, &focusable{}
+	container := &Base{}
+	root.AppendChild(first)
+	root.AppendChild(container)
+	container.AppendChild(second)
+	container.AppendChild(plain)
+	root.AppendChild(skipped)
+	root.AppendChild(third)
+
+	// Tree order by default, plain components are not focusable
+	expected := ComponentList{first, second, skipped, third}
+	if order := FocusOrder(root); !sameComponents(order, expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+
+	// Positive indexes go first, negative ones are skipped
+	third.SetTabIndex(1)
+	skipped.SetTabIndex(-1)
+	plain.SetFocusable(true)
+	expected = ComponentList{third, first, second, plain}
+	if order := FocusOrder(root); !sameComponents(order, expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+}
@@ -0,0 +1,50 @@
+package components
+
+import "errors"
+
+// ID errors
+var (
+	ErrConflictingID = errors.New("Id and Name must be the same if both are set")
+)
+
+// SetID sets the name the component can be found with, see FindByID
+func (c *Base) SetID(id string) {
+	c.id = id
+}
+
+// ID returns the name the component can be found with, empty if it has none
+func (c *Base) ID() string {
+	return c.id
+}
+
+// FindByID returns the first component in root's tree (root included, parents before their
+// children) with an ID, or nil if there is none
+func FindByID(root Component, id string) Component {
+	if id == "" {
+		return nil
+	}
+	if root.ID() == id {
+		return root
+	}
+	for _, child := range root.Children() {
+		if found := FindByID(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// ParseID sets a component's ID from its Id or Name attribute (x:Name also works, since
+// namespaces are not considered)
+func ParseID(c Component, list AttributeList) error {
+	id, hasID := list["Id"]
+	name, hasName := list["Name"]
+	if hasID && hasName && id != name {
+		return ErrConflictingID
+	}
+	if hasName {
+		id = name
+	}
+	c.SetID(id.String())
+	return nil
+}
@@ -0,0 +1,37 @@
+package components
+
+import "testing"
+
+func TestFindByID(t *testing.T) {
+	root := &Base{}
+	container, first, second := &Base{}, &Base{}, &Base{}
+	root.AppendChild(container)
+	container.AppendChild(first)
+	root.AppendChild(second)
+
+	if err := ParseID(container, AttributeList{"Id": "container"}); err != nil {
+		t.Fatalf("Id failed: %s", err.Error())
+	}
+	if err := ParseID(first, AttributeList{"Name": "item"}); err != nil {
+		t.Fatalf("Name failed: %s", err.Error())
+	}
+	second.SetID("item")
+
+	if found := FindByID(root, "container"); found != container {
+		t.Errorf("Expected container, got %v", found)
+	}
+	// Parents and earlier children come first
+	if found := FindByID(root, "item"); found != first {
+		t.Errorf("Expected first item, got %v", found)
+	}
+	if found := FindByID(root, "missing"); found != nil {
+		t.Errorf("Expected nothing, got %v", found)
+	}
+	if found := FindByID(root, ""); found != nil {
+		t.Errorf("Empty ID should match nothing, got %v", found)
+	}
+
+	if err := ParseID(root, AttributeList{"Id": "a", "Name": "b"}); err != ErrConflictingID {
+		t.Errorf("Expected conflicting ID error, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package components
+
+import "errors"
+
+// Layout works in two passes, both in pixels:
+//   - Measure asks a component how big it would like to be, given how much space is available
+//     (the available size can be infinite on an axis, use Unbounded to check)
+//   - Arrange tells a component its final position and size, containers then arrange their children
+// The root starts both passes, usually right before drawing.
+
+// Unbounded is used as available size on axes with no size limit
+const Unbounded = float32(1 << 30)
+
+// Measure returns the biggest size among the children (which overlap by default)
+func (c *Base) Measure(available Size) Size {
+	var desired Size
+	for _, child := range c.children {
+		size := child.Measure(available)
+		if size.Width > desired.Width {
+			desired.Width = size.Width
+		}
+		if size.Height > desired.Height {
+			desired.Height = size.Height
+		}
+	}
+	return c.Constrain(desired)
+}
+
+// Arrange sets the component bounds and gives the same space to all children
+func (c *Base) Arrange(pixels Bounds) {
+	c.SetPixelBounds(pixels)
+	for _, child := range c.children {
+		child.Arrange(pixels)
+	}
+}
+
+// SetPixelBounds sets the component bounds from pixel bounds, the root keeps them as they are
+func (c *Base) SetPixelBounds(pixels Bounds) {
+	if c.isRoot() {
+		c.SetBounds(pixels)
+		return
+	}
+	c.SetBounds(c.ToRelative(pixels))
+}
+
+// SetConstraints sets the minimum and maximum size (in pixels) the component can have.
+// Zero on any maximum axis means there is no limit.
+func (c *Base) SetConstraints(min, max Size) {
+	c.minSize = min
+	c.maxSize = max
+}
+
+// Constraints returns the minimum and maximum size the component can have
+func (c *Base) Constraints() (min, max Size) {
+	return c.minSize, c.maxSize
+}
+
+// Constrain clamps a size between the component's minimum and maximum size
+func (c *Base) Constrain(size Size) Size {
+	return ConstrainSize(size, c.minSize, c.maxSize)
+}
+
+// ConstrainSize clamps a size between a minimum and a maximum size (zero meaning no maximum)
+func ConstrainSize(size, min, max Size) Size {
+	if max.Width > 0 && size.Width > max.Width {
+		size.Width = max.Width
+	}
+	if max.Height > 0 && size.Height > max.Height {
+		size.Height = max.Height
+	}
+	if size.Width < min.Width {
+		size.Width = min.Width
+	}
+	if size.Height < min.Height {
+		size.Height = min.Height
+	}
+	return size
+}
+
+// ErrInvalidConstraint means a size constraint attribute is not a valid size
+var ErrInvalidConstraint = errors.New("MinWidth, MinHeight, MaxWidth and MaxHeight must be positive numbers")
+
+// ParseConstraints reads the MinWidth, MinHeight, MaxWidth and MaxHeight attributes,
+// which are supported by every component
+func ParseConstraints(list AttributeList) (min, max Size, err error) {
+	fields := map[string]*float32{
+		"MinWidth":  &min.Width,
+		"MinHeight": &min.Height,
+		"MaxWidth":  &max.Width,
+		"MaxHeight": &max.Height,
+	}
+	for name, field := range fields {
+		attr, ok := list[name]
+		if !ok {
+			continue
+		}
+		*field, err = attr.Float32()
+		if err != nil || *field < 0 {
+			return min, max, ErrInvalidConstraint
+		}
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package components
+
+import "reflect"
+
+// StateHolder is implemented by components holding state changed by the user, such as
+// typed text or scroll offsets, so that it can be kept when a tree is made again
+type StateHolder interface {
+	// State returns a copy of the component's state
+	State() interface{}
+	// SetState restores a state returned by State on a component of the same type, without
+	// calling change callbacks
+	SetState(interface{})
+}
+
+// MatchComponents pairs the components of a new tree with the ones in an old tree they most
+// likely replace: the ones with the same ID, or else the ones in the same position. Only
+// components of the same type are paired. The result maps new components to old ones.
+func MatchComponents(from, to Component) map[Component]Component {
+	ids := make(map[string]Component)
+	var walk func(Component)
+	walk = func(c Component) {
+		if id := c.ID(); id != "" {
+			if _, ok := ids[id]; !ok {
+				ids[id] = c
+			}
+		}
+		for _, child := range c.Children() {
+			walk(child)
+		}
+	}
+	walk(from)
+
+	matches := make(map[Component]Component)
+	matchComponents(from, to, ids, matches)
+	return matches
+}
+
+func matchComponents(from, to Component, ids map[string]Component, matches map[Component]Component) {
+	if id := to.ID(); id != "" {
+		if old, ok := ids[id]; ok {
+			from = old
+		}
+	}
+	if from != nil && reflect.TypeOf(from) == reflect.TypeOf(to) {
+		matches[to] = from
+	}
+
+	// Children are paired by position, even if their parents didn't match
+	var children ComponentList
+	if from != nil {
+		children = from.Children()
+	}
+	for i, child := range to.Children() {
+		var old Component
+		if i < len(children) {
+			old = children[i]
+		}
+		matchComponents(old, child, ids, matches)
+	}
+}
+
+// CopyState gives the components of a new tree the state of the matching ones in an old
+// tree, see MatchComponents
+func CopyState(from, to Component) {
+	for current, old := range MatchComponents(from, to) {
+		holder, ok := current.(StateHolder)
+		if !ok {
+			continue
+		}
+		holder.SetState(old.(StateHolder).State())
+	}
+}
@@ -0,0 +1,50 @@
+package components
+
+import "testing"
+
+// stateful is a component holding a string as state
+type stateful struct {
+	Base
+	value string
+}
+
+func (s *stateful) State() interface{} {
+	return s.value
+}
+
+func (s *stateful) SetState(state interface{}) {
+	s.value = state.(string)
+}
+
+func TestCopyState(t *testing.T) {
+	old := &Base{}
+	oldFirst, oldSecond, oldNamed := &stateful{value: "first"}, &stateful{value: "second"}, &stateful{value: "named"}
+	oldNamed.SetID("named")
+	old.AppendChild(oldFirst)
+	old.AppendChild(oldSecond)
+	old.AppendChild(oldNamed)
+
+	// The named component moved to the front, the second one changed type
+	tree := &Base{}
+	named, first, second := &stateful{}, &stateful{}, &Base{}
+	named.SetID("named")
+	tree.AppendChild(named)
+	tree.AppendChild(first)
+	tree.AppendChild(second)
+
+	CopyState(old, tree)
+	if named.value != "named" {
+		t.Errorf("Expected state to follow the ID, got %q", named.value)
+	}
+	if first.value != "second" {
+		t.Errorf("Expected state from the same position, got %q", first.value)
+	}
+
+	matches := MatchComponents(old, tree)
+	if _, ok := matches[second]; ok {
+		t.Error("Components of different types should not match")
+	}
+	if matches[tree] != old {
+		t.Error("Expected roots to match")
+	}
+}
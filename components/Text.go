@@ -1,8 +1,37 @@
 package components
 
 import (
+	"fmt"
+	"image"
+	"image/color"
+
 	"github.com/hamcha/youi/font"
-	"github.com/hamcha/youi/opengl"
+	"github.com/hamcha/youi/render"
+)
+
+// DefaultFontSize is the font size used by text components when none is set
+const DefaultFontSize = 16
+
+// TextAlign is the horizontal alignment of text inside its bounds
+type TextAlign int
+
+// Horizontal text alignments
+const (
+	TextAlignLeft TextAlign = iota
+	TextAlignCenter
+	TextAlignRight
+	// TextAlignJustify stretches wrapped lines to fill the whole width
+	TextAlignJustify
+)
+
+// VerticalAlign is the vertical alignment of text inside its bounds
+type VerticalAlign int
+
+// Vertical text alignments
+const (
+	VerticalAlignTop VerticalAlign = iota
+	VerticalAlignCenter
+	VerticalAlignBottom
 )
 
 // Text is a common parent of all text-based components
@@ -13,67 +42,409 @@ type Text struct {
 	fontSize float64
 
 	font      *font.Font
-	text      *opengl.Text
+	fontErr   error
+	text      render.Text
 	dirtyFont bool
 
 	content      string
+	runs         []TextRun
 	dirtyContent bool
+
+	color      color.Color
+	dirtyColor bool
+
+	align         TextAlign
+	verticalAlign VerticalAlign
+	wrap          bool
+	lineHeight    float32
+	direction     font.Direction
+	padding       Insets
+
+	layout      *font.TextLayout
+	layoutWidth float32
+	dirtyLayout bool
+	drawnLayout *font.TextLayout
 }
 
 // SetText changes the text content of the text control
 func (c *Text) SetText(str string) {
 	c.content = str
+	c.runs = nil
 	c.dirtyContent = true
+	c.dirtyLayout = true
 }
 
+// Content returns the text content
+func (c *Text) Content() string {
+	return c.content
+}
+
+// SetFontFace sets the font by name (see font.LoadFont), empty means the default font
 func (c *Text) SetFontFace(name string) {
 	c.fontFace = name
 	c.dirtyFont = true
+	c.dirtyLayout = true
+}
+
+// FontFace returns the font name
+func (c *Text) FontFace() string {
+	return c.fontFace
 }
 
+// SetFontSize sets the font size in pixels
 func (c *Text) SetFontSize(size float64) {
 	c.fontSize = size
+	c.dirtyFont = true
+	c.dirtyLayout = true
+}
+
+// FontSize returns the font size in pixels
+func (c *Text) FontSize() float64 {
+	if c.fontSize <= 0 {
+		return DefaultFontSize
+	}
+	return c.fontSize
+}
+
+// SetColor sets the text color
+func (c *Text) SetColor(col color.Color) {
+	c.color = col
+	c.dirtyColor = true
+}
+
+// Color returns the text color
+func (c *Text) Color() color.Color {
+	if c.color == nil {
+		return color.White
+	}
+	return c.color
+}
+
+// SetAlign sets how the text is aligned horizontally inside the component bounds
+func (c *Text) SetAlign(align TextAlign) {
+	c.align = align
+	c.dirtyContent = true
+	c.dirtyLayout = true
+}
+
+// Align returns the horizontal text alignment
+func (c *Text) Align() TextAlign {
+	return c.align
 }
 
+// SetVerticalAlign sets how the text is aligned vertically inside the component bounds
+func (c *Text) SetVerticalAlign(align VerticalAlign) {
+	c.verticalAlign = align
+	c.SetRedraw()
+}
+
+// VerticalAlign returns the vertical text alignment
+func (c *Text) VerticalAlign() VerticalAlign {
+	return c.verticalAlign
+}
+
+// SetWrap sets whether the text should be wrapped to the component width
+func (c *Text) SetWrap(wrap bool) {
+	c.wrap = wrap
+	c.dirtyContent = true
+	c.dirtyLayout = true
+}
+
+// Wrap returns whether the text is wrapped to the component width
+func (c *Text) Wrap() bool {
+	return c.wrap
+}
+
+// SetLineHeight sets the distance between lines, as a multiple of the font's line height
+func (c *Text) SetLineHeight(height float32) {
+	c.lineHeight = height
+	c.dirtyContent = true
+	c.dirtyLayout = true
+}
+
+// SetDirection sets the base direction of the text paragraphs (detected from the text by default)
+func (c *Text) SetDirection(direction font.Direction) {
+	c.direction = direction
+	c.dirtyContent = true
+	c.dirtyLayout = true
+}
+
+// Direction returns the base direction of the text paragraphs
+func (c *Text) Direction() font.Direction {
+	return c.direction
+}
+
+// SetPadding sets the space between the component bounds and the text, in pixels
+func (c *Text) SetPadding(padding Insets) {
+	c.padding = padding
+	c.dirtyContent = true
+	c.dirtyLayout = true
+}
+
+// Padding returns the space between the component bounds and the text
+func (c *Text) Padding() Insets {
+	return c.padding
+}
+
+// LineHeight returns the line height multiplier (defaults to 1)
+func (c *Text) LineHeight() float32 {
+	if c.lineHeight <= 0 {
+		return 1
+	}
+	return c.lineHeight
+}
+
+// makeFace loads the font face, falling back to the default font (Go Regular) when there
+// is none or it can't be loaded
 func (c *Text) makeFace() {
-	// If no font is provided, use Go Regolar
-	if c.fontFace == "" {
-		c.font = font.DefaultFont()
-	} else {
+	c.fontErr = nil
+	if c.fontFace != "" {
 		var err error
-		c.font, err = font.LoadFont(c.fontFace)
-		if err != nil {
-			//TODO Proper error reporting
-			panic(err)
+		if c.font, err = font.LoadFont(c.fontFace); err == nil {
+			return
 		}
+		c.fontErr = fmt.Errorf("could not load font \"%s\": %s", c.fontFace, err.Error())
 	}
+	c.font = font.DefaultFont()
+}
+
+// FontError returns why the font face could not be loaded the last time the text was laid
+// out (the default font is used instead), or nil if it was
+func (c *Text) FontError() error {
+	return c.fontErr
+}
+
+// scale returns the ratio between the font size and the font texture size
+func (c *Text) scale() float32 {
+	return float32(c.FontSize()) / float32(c.font.Size)
+}
+
+// textLayout returns the text laid out for the given width in pixels (only used when
+// wrapping), the last layout is kept until something changes
+func (c *Text) textLayout(width float32) *font.TextLayout {
+	if c.font == nil || c.dirtyFont {
+		c.makeFace()
+	}
+
+	scale := c.scale()
+	maxWidth := float32(0)
+	if c.wrap && width < Unbounded {
+		maxWidth = (width - c.padding.Left - c.padding.Right) / scale
+		if maxWidth <= 0 {
+			// Zero means no wrapping, wrap every word instead
+			maxWidth = 1
+		}
+	}
+
+	if c.layout == nil || c.dirtyLayout || c.layoutWidth != maxWidth {
+		c.layout = c.font.LayoutSpans(c.spans(), font.LayoutOptions{
+			MaxWidth:   maxWidth,
+			LineHeight: c.LineHeight(),
+			Align:      c.paragraphAlign(),
+			Direction:  c.direction,
+		})
+		c.layoutWidth = maxWidth
+		c.dirtyLayout = false
+	}
+	return c.layout
+}
+
+// paragraphAlign returns how lines are aligned among themselves
+func (c *Text) paragraphAlign() font.Align {
+	switch c.align {
+	case TextAlignCenter:
+		return font.AlignCenter
+	case TextAlignRight:
+		return font.AlignRight
+	case TextAlignJustify:
+		return font.AlignJustify
+	}
+	return font.AlignLeft
+}
+
+// textSize returns the size of the laid out text, in pixels
+func (c *Text) textSize(width float32) Size {
+	layout := c.textLayout(width)
+	scale := c.scale()
+	return Size{
+		Width:  layout.Width * scale,
+		Height: layout.Height * scale,
+	}
+}
+
+// Measure returns the size of the text (and padding), wrapped to the available width if
+// wrapping is enabled
+func (c *Text) Measure(available Size) Size {
+	size := c.textSize(available.Width)
+	size.Width += c.padding.Left + c.padding.Right
+	size.Height += c.padding.Top + c.padding.Bottom
+	return c.Constrain(size)
 }
 
 func (c *Text) ShouldDraw() bool {
-	return c.dirtyFont || c.dirtyContent
+	return c.dirtyFont || c.dirtyContent || c.dirtyColor
 }
 
 func (c *Text) ClearFlags() {
 	c.dirtyFont = false
 	c.dirtyContent = false
+	c.dirtyColor = false
 }
 
-func (c *Text) Draw() {
+func (c *Text) Draw(r render.Renderer) {
 	if c.font == nil || c.text == nil || c.dirtyFont {
 		c.makeFace()
-		c.text = opengl.MakeText(c.font, c.content)
-	} else if c.dirtyContent {
-		c.text.SetContent(c.content)
+		c.dirtyLayout = true
+		c.Destroy()
+		c.text = r.MakeText(c.font)
+		c.text.SetSize(c.FontSize())
+		c.text.SetColor(c.Color())
+		c.drawnLayout = nil
+	} else if c.dirtyColor {
+		c.text.SetColor(c.Color())
 	}
 
-	// Check if bounds have changed
-	if c.dirtyBounds {
-		// Update transform matrix
-		mat := getTransformMatrix(c.bounds)
-		c.text.Shader.GetUniform("transform").Set(mat)
+	// Only send the layout again if it changed since last time
+	if layout := c.textLayout(c.PixelBounds().Size.Width); layout != c.drawnLayout {
+		c.text.SetLayout(layout)
+		c.drawnLayout = layout
 	}
 
-	c.text.Mesh.Draw()
-	c.Base.Draw()
+	r.DrawText(c.text, c.textRect(r.Size()))
+	c.Base.Draw(r)
 	c.ClearFlags()
 }
+
+// Destroy frees the text held by the renderer, it's made again if the component is drawn
+func (c *Text) Destroy() {
+	if c.text != nil {
+		c.text.Destroy()
+		c.text = nil
+	}
+}
+
+// textRect returns where the text goes inside the component bounds, according to alignment
+func (c *Text) textRect(surface image.Point) render.Rect {
+	w, h := float32(surface.X), float32(surface.Y)
+	rect := c.bounds.Shrink(Insets{
+		Left:   c.padding.Left / w,
+		Top:    c.padding.Top / h,
+		Right:  c.padding.Right / w,
+		Bottom: c.padding.Bottom / h,
+	}).Rect()
+	size := c.textSize(c.PixelBounds().Size.Width)
+	free := Size{
+		Width:  rect.Width - size.Width/float32(surface.X),
+		Height: rect.Height - size.Height/float32(surface.Y),
+	}
+
+	switch c.align {
+	case TextAlignCenter:
+		rect.X += free.Width / 2
+	case TextAlignRight:
+		rect.X += free.Width
+	}
+
+	switch c.verticalAlign {
+	case VerticalAlignCenter:
+		rect.Y += free.Height / 2
+	case VerticalAlignBottom:
+		rect.Y += free.Height
+	}
+
+	return rect
+}
+
+// TextLayout returns the content laid out for the current bounds, measures in it are in
+// font texture pixels (see TextScale)
+func (c *Text) TextLayout() *font.TextLayout {
+	return c.textLayout(c.PixelBounds().Size.Width)
+}
+
+// TextScale returns the ratio between pixels on screen and measures in TextLayout
+func (c *Text) TextScale() float32 {
+	if c.font == nil || c.dirtyFont {
+		c.makeFace()
+	}
+	return c.scale()
+}
+
+// surfaceSize returns the size of the surface the text is drawn on, in pixels
+func (c *Text) surfaceSize() image.Point {
+	size := c.Root().Bounds().Size
+	return image.Point{int(size.Width), int(size.Height)}
+}
+
+// toRelative converts a rectangle in the text layout to bounds relative to the root
+func (c *Text) toRelative(rect font.Rect) render.Rect {
+	surface := c.surfaceSize()
+	origin := c.textRect(surface)
+	scale := c.TextScale()
+	w, h := float32(surface.X), float32(surface.Y)
+	return render.Rect{
+		X:      origin.X + rect.X*scale/w,
+		Y:      origin.Y + rect.Y*scale/h,
+		Width:  rect.Width * scale / w,
+		Height: rect.Height * scale / h,
+	}
+}
+
+// IndexAt returns the byte offset of the content under a position (relative to the root,
+// like Bounds), see font.TextLayout.IndexAt
+func (c *Text) IndexAt(pos Position) int {
+	layout := c.TextLayout()
+	surface := c.surfaceSize()
+	if surface.X == 0 || surface.Y == 0 {
+		return 0
+	}
+	origin := c.textRect(surface)
+	scale := c.TextScale()
+	return layout.IndexAt(
+		(pos.X-origin.X)*float32(surface.X)/scale,
+		(pos.Y-origin.Y)*float32(surface.Y)/scale)
+}
+
+// CaretRect returns where a one pixel wide caret goes before the character at a byte offset
+// of the content, relative to the root
+func (c *Text) CaretRect(index int) render.Rect {
+	caret := c.TextLayout().CaretAt(index)
+	rect := c.toRelative(font.Rect{X: caret.X, Y: caret.Y, Height: caret.Height})
+	if surface := c.surfaceSize(); surface.X > 0 {
+		rect.Width = 1 / float32(surface.X)
+	}
+	return rect
+}
+
+// SelectionRects returns the rectangles (one per line) covering the content between two
+// byte offsets, relative to the root
+func (c *Text) SelectionRects(from, to int) []render.Rect {
+	if from > to {
+		from, to = to, from
+	}
+	layout := c.TextLayout()
+	if from == to || len(layout.Lines) == 0 {
+		return nil
+	}
+
+	var rects []render.Rect
+	for i := layout.LineAt(from); i <= layout.LineAt(to) && i < len(layout.Lines); i++ {
+		line := layout.Lines[i]
+		start, end := line.TextStart, line.TextEnd
+		if from > start {
+			start = from
+		}
+		if to < end {
+			end = to
+		}
+		left, right := layout.CaretOnLine(start, i).X, layout.CaretOnLine(end, i).X
+		if left > right {
+			left, right = right, left
+		}
+		// Selected newlines show up as a bit of space at the end of the line
+		if to > line.TextEnd && i < len(layout.Lines)-1 {
+			right += layout.LineHeight / 4
+		}
+		rects = append(rects, c.toRelative(font.Rect{X: left, Y: line.Y, Width: right - left, Height: line.Height}))
+	}
+	return rects
+}
@@ -0,0 +1,138 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/hamcha/youi/font"
+)
+
+// TextStyle is the style of a run of text, zero values mean "same as the text component"
+type TextStyle struct {
+	FontFace string
+	FontSize float64
+	Color    color.Color
+
+	Bold, Italic, Underline, Strikethrough bool
+}
+
+// TextRun is a piece of text with its own style
+type TextRun struct {
+	Text  string
+	Style TextStyle
+}
+
+// InlineContentHandler is implemented by components that accept styled text as content,
+// such as <Label>Hello <Bold>world</Bold></Label>
+type InlineContentHandler interface {
+	SetInlineContent(runs []TextRun) error
+}
+
+// ParseTextStyle reads the style attributes of an inline span (FontFace, FontSize, Color,
+// Bold, Italic, Underline, Strikethrough) on top of the style of its parent
+func ParseTextStyle(list AttributeList, parent TextStyle) (TextStyle, error) {
+	style := parent
+	if face, ok := list["FontFace"]; ok {
+		style.FontFace = face.String()
+	}
+	if size, ok := list["FontSize"]; ok {
+		value, err := size.Float32()
+		if err != nil || value <= 0 {
+			return style, errors.New("FontSize must be a positive number")
+		}
+		style.FontSize = float64(value)
+	}
+	if col, ok := list["Color"]; ok {
+		hcol, err := col.Color()
+		if err != nil {
+			return style, err
+		}
+		style.Color = hcol
+	}
+
+	flags := []struct {
+		name  string
+		value *bool
+	}{
+		{"Bold", &style.Bold},
+		{"Italic", &style.Italic},
+		{"Underline", &style.Underline},
+		{"Strikethrough", &style.Strikethrough},
+	}
+	for _, flag := range flags {
+		if attr, ok := list[flag.name]; ok {
+			value, err := attr.Bool()
+			if err != nil {
+				return style, fmt.Errorf("%s must be either true or false", flag.name)
+			}
+			*flag.value = value
+		}
+	}
+	return style, nil
+}
+
+// span converts a run to a font span, with sizes relative to the text component's
+func (r TextRun) span(size float64) font.Span {
+	span := font.Span{
+		Text:          r.Text,
+		Color:         r.Style.Color,
+		Bold:          r.Style.Bold,
+		Italic:        r.Style.Italic,
+		Underline:     r.Style.Underline,
+		Strikethrough: r.Style.Strikethrough,
+	}
+	if r.Style.FontFace != "" {
+		// Runs with fonts that can't be loaded just use the component's font
+		if fnt, err := font.LoadFont(r.Style.FontFace); err == nil {
+			span.Font = fnt
+		}
+	}
+	if r.Style.FontSize > 0 {
+		span.Scale = float32(r.Style.FontSize / size)
+	}
+	return span
+}
+
+// SetRuns replaces the text content with styled runs of text
+func (c *Text) SetRuns(runs []TextRun) {
+	c.runs = runs
+	c.content = ""
+	for _, run := range runs {
+		c.content += run.Text
+	}
+	c.dirtyContent = true
+	c.dirtyLayout = true
+}
+
+// Runs returns the styled runs of text, which is nil if the content was set with SetText
+func (c *Text) Runs() []TextRun {
+	return c.runs
+}
+
+// SetInlineContent sets the styled runs of text coming from YUML content
+func (c *Text) SetInlineContent(runs []TextRun) error {
+	// Load fonts now, so we can report errors
+	for _, run := range runs {
+		if run.Style.FontFace == "" {
+			continue
+		}
+		if _, err := font.LoadFont(run.Style.FontFace); err != nil {
+			return fmt.Errorf("could not load font \"%s\": %s", run.Style.FontFace, err.Error())
+		}
+	}
+	c.SetRuns(runs)
+	return nil
+}
+
+// spans returns the text content as spans to lay out
+func (c *Text) spans() []font.Span {
+	if c.runs == nil {
+		return []font.Span{{Text: c.content}}
+	}
+	spans := make([]font.Span, len(c.runs))
+	for i, run := range c.runs {
+		spans[i] = run.span(c.FontSize())
+	}
+	return spans
+}
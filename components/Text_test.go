@@ -0,0 +1,54 @@
+package components
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hamcha/youi/font"
+)
+
+// fakeText is a render.Text that remembers being destroyed
+type fakeText struct {
+	destroyed bool
+}
+
+func (t *fakeText) SetLayout(*font.TextLayout) {}
+func (t *fakeText) SetColor(color.Color)       {}
+func (t *fakeText) SetSize(float64)            {}
+func (t *fakeText) Destroy()                   { t.destroyed = true }
+
+func TestTextMissingFont(t *testing.T) {
+	text := &Text{}
+	text.SetText("Hello")
+	text.SetFontFace("missing-font")
+
+	// Fonts that can't be loaded are reported, and the default one is used
+	size := text.Measure(Size{Width: Unbounded, Height: Unbounded})
+	if size.Width <= 0 || text.font != font.DefaultFont() {
+		t.Errorf("Expected the text to be laid out with the default font, got width %g", size.Width)
+	}
+	if text.FontError() == nil {
+		t.Error("Expected an error for the missing font")
+	}
+
+	text.SetFontFace("")
+	text.Measure(Size{Width: Unbounded, Height: Unbounded})
+	if text.FontError() != nil {
+		t.Errorf("Expected no error for the default font, got %s", text.FontError().Error())
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	root := &Base{}
+	text := &Text{}
+	root.AppendChild(text)
+	rendered := &fakeText{}
+	text.text = rendered
+
+	// The whole tree is destroyed, and texts are made again when drawn
+	Destroy(root)
+	if !rendered.destroyed || text.text != nil {
+		t.Error("Expected the text of the child to be destroyed")
+	}
+	Destroy(root)
+}
@@ -0,0 +1,136 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// DefaultButtonPadding is the space around button labels, in pixels
+var DefaultButtonPadding = components.Insets{Left: 8, Top: 4, Right: 8, Bottom: 4}
+
+// Button is a clickable control with a text label
+type Button struct {
+	components.Text
+	components.ControlState
+
+	// Colors are the background colors for each visual state
+	Colors components.StateColors
+	// OnClick is called when the button is clicked, or activated with the keyboard
+	OnClick func()
+
+	// activate is what the button does before calling OnClick (eg. toggling)
+	activate func()
+}
+
+// MakeButton creates a button with a text label
+func MakeButton(label string) *Button {
+	button := new(Button)
+	button.init(label)
+	return button
+}
+
+func (b *Button) init(label string) {
+	b.Colors = DefaultControlColors
+	b.SetText(label)
+	b.SetAlign(components.TextAlignCenter)
+	b.SetVerticalAlign(components.VerticalAlignCenter)
+	b.SetPadding(DefaultButtonPadding)
+	b.BindEvents(b)
+	b.AddEventListener(components.EventClick, func(ev *components.Event) {
+		if ev.Button == input.MouseButtonLeft {
+			b.Click()
+		}
+	})
+	b.AddEventListener(components.EventKeyDown, func(ev *components.Event) {
+		if b.Focused() && (ev.Key == input.KeySpace || ev.Key == input.KeyEnter) && !ev.Repeat {
+			b.Click()
+			ev.StopPropagation()
+		}
+	})
+}
+
+// Click activates the button as if the user clicked it, unless it's disabled
+func (b *Button) Click() {
+	if b.Disabled() {
+		return
+	}
+	if b.activate != nil {
+		b.activate()
+	}
+	if b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+// ShouldDraw returns whether the button needs to be re-drawn
+func (b *Button) ShouldDraw() bool {
+	return b.Text.ShouldDraw() || b.Base.ShouldDraw() || b.StateChanged()
+}
+
+// Draw draws the button background, label and focus ring
+func (b *Button) Draw(r render.Renderer) {
+	b.drawWithColors(r, b.Colors)
+}
+
+func (b *Button) drawWithColors(r render.Renderer, colors components.StateColors) {
+	rect := b.Bounds().Rect()
+	if col := colors.Get(b.VisualState()); col != nil {
+		r.FillRect(rect, col)
+	}
+	b.Text.Draw(r)
+	b.DrawFocusRing(r, rect)
+	b.ClearState()
+}
+
+func (b *Button) String() string {
+	return fmt.Sprintf(`<Button Text="%s" FontFace="%s" FontSize="%g" Color="%s" Padding="%s" Disabled="%t" %s />`,
+		escapeAttribute(b.Content()), escapeAttribute(b.FontFace()), b.FontSize(), utils.ToHexColor(b.Color()),
+		b.Padding(), b.Disabled(), formatStateColors(b.Colors))
+}
+
+// SetAttribute changes a text attribute or Disabled, for bindings
+func (b *Button) SetAttribute(name string, value components.Attribute) error {
+	if name == "Disabled" {
+		return setDisabled(&b.ControlState, value)
+	}
+	return setTextAttribute(&b.Text, name, value)
+}
+
+// parseButtonAttributes applies the attributes shared by all kinds of buttons
+func parseButtonAttributes(button *Button, list components.AttributeList) error {
+	// Buttons have different defaults than other text
+	if _, ok := list["HorizontalAlign"]; !ok {
+		list["HorizontalAlign"] = "Center"
+	}
+	if _, ok := list["VerticalAlign"]; !ok {
+		list["VerticalAlign"] = "Center"
+	}
+	if _, ok := list["Padding"]; !ok {
+		list["Padding"] = components.Attribute(DefaultButtonPadding.String())
+	}
+	if err := parseTextAttributes(&button.Text, list); err != nil {
+		return err
+	}
+	if err := parseControlAttributes(&button.ControlState, list); err != nil {
+		return err
+	}
+
+	colors, err := parseStateColors(list, button.Colors)
+	if err != nil {
+		return err
+	}
+	button.Colors = colors
+	return nil
+}
+
+func makeButton(list components.AttributeList) (components.Component, error) {
+	button := MakeButton("")
+	if err := parseButtonAttributes(button, list); err != nil {
+		return nil, err
+	}
+	return button, nil
+}
@@ -37,27 +37,14 @@ func (c *Canvas) SetBounds(bounds components.Bounds) {
 	c.SetRedraw()
 }
 
-func (c *Canvas) Draw() {
-	//if c.Base.ShouldDraw() {
-	c.resizeChildren()
-	//}
-
-	c.Base.Draw()
+// Measure returns the canvas size, since it's fixed
+func (c *Canvas) Measure(available components.Size) components.Size {
+	return components.BoundsFromRect(c.canvasBounds).Size
 }
 
-func (c *Canvas) resizeChildren() {
-	// Recalculate size in relation to the root
-
-	// Get resolution
-	res := c.Root().Bounds().Size
-
-	// Convert from absolute to relative bounds
-	relbounds := components.BoundsFromRect(c.canvasBounds).Scale(res.Inverse())
-
-	// Apply to each children
-	for _, child := range c.Children() {
-		child.SetBounds(relbounds)
-	}
+// Arrange ignores the space given by the parent and uses the canvas rectangle instead
+func (c *Canvas) Arrange(pixels components.Bounds) {
+	c.Base.Arrange(components.BoundsFromRect(c.canvasBounds))
 }
 
 func (c *Canvas) String() string {
@@ -0,0 +1,209 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// checkGap is the space between the box of check boxes and radio buttons and their label
+const checkGap = 6
+
+// CheckBox is a box that can be checked or unchecked, with a text label on its right
+type CheckBox struct {
+	components.Text
+	components.ControlState
+
+	// Colors are the box colors for each visual state
+	Colors components.StateColors
+	// OnChange is called when the box is checked or unchecked by the user
+	OnChange func(checked bool)
+
+	checked bool
+	// activate is what clicking does, toggling for check boxes
+	activate func()
+	// markInset is how much smaller than the box the check mark is, as a fraction of the box
+	markInset float32
+}
+
+// MakeCheckBox creates an unchecked check box with a text label
+func MakeCheckBox(label string) *CheckBox {
+	box := new(CheckBox)
+	box.init(label)
+	box.activate = func() {
+		box.SetChecked(!box.checked)
+		box.changed()
+	}
+	return box
+}
+
+func (c *CheckBox) init(label string) {
+	c.Colors = DefaultControlColors
+	c.markInset = 0.2
+	c.SetText(label)
+	c.SetFontSize(0)
+	c.SetVerticalAlign(components.VerticalAlignCenter)
+	c.BindEvents(c)
+	c.AddEventListener(components.EventClick, func(ev *components.Event) {
+		if ev.Button == input.MouseButtonLeft {
+			c.Click()
+		}
+	})
+	c.AddEventListener(components.EventKeyDown, func(ev *components.Event) {
+		if c.Focused() && ev.Key == input.KeySpace && !ev.Repeat {
+			c.Click()
+			ev.StopPropagation()
+		}
+	})
+}
+
+// SetFontSize sets the label font size, the box is as big as the text
+func (c *CheckBox) SetFontSize(size float64) {
+	c.Text.SetFontSize(size)
+	c.SetPadding(components.Insets{Left: float32(c.FontSize()) + checkGap})
+}
+
+// Click toggles the box as if the user clicked it, unless it's disabled
+func (c *CheckBox) Click() {
+	if !c.Disabled() {
+		c.activate()
+	}
+}
+
+func (c *CheckBox) changed() {
+	if c.OnChange != nil {
+		c.OnChange(c.checked)
+	}
+	notifyChange(c)
+}
+
+// SetChecked sets whether the box is checked, without calling OnChange
+func (c *CheckBox) SetChecked(checked bool) {
+	c.checked = checked
+	c.SetRedraw()
+}
+
+// Checked returns whether the box is checked
+func (c *CheckBox) Checked() bool {
+	return c.checked
+}
+
+// State returns whether the box is checked, see components.StateHolder
+func (c *CheckBox) State() interface{} {
+	return c.checked
+}
+
+// SetState restores whether the box is checked
+func (c *CheckBox) SetState(state interface{}) {
+	if checked, ok := state.(bool); ok {
+		c.SetChecked(checked)
+	}
+}
+
+// ShouldDraw returns whether the check box needs to be re-drawn
+func (c *CheckBox) ShouldDraw() bool {
+	return c.Text.ShouldDraw() || c.Base.ShouldDraw() || c.StateChanged()
+}
+
+// Draw draws the box (with the check mark if checked), the label and the focus ring
+func (c *CheckBox) Draw(r render.Renderer) {
+	bounds := c.Bounds().Rect()
+	surface := r.Size()
+
+	// The box is a square as big as the font size, vertically centered
+	size := float32(c.FontSize())
+	box := render.Rect{
+		X:      bounds.X,
+		Y:      bounds.Y + (bounds.Height-size/float32(surface.Y))/2,
+		Width:  size / float32(surface.X),
+		Height: size / float32(surface.Y),
+	}
+	r.FillRect(box, c.Colors.Get(c.VisualState()))
+	if c.checked {
+		mark := DefaultAccentColor
+		if c.Disabled() {
+			mark = DefaultDisabledColor
+		}
+		r.FillRect(insetRect(r, box, size*c.markInset), mark)
+	}
+
+	c.Text.Draw(r)
+	c.DrawFocusRing(r, box)
+	c.ClearState()
+}
+
+// SetAttribute changes Checked, Disabled or a text attribute, for bindings
+func (c *CheckBox) SetAttribute(name string, value components.Attribute) error {
+	switch name {
+	case "Checked":
+		checked, err := value.Bool()
+		if err != nil {
+			return errors.New("Checked must be either true or false")
+		}
+		c.SetChecked(checked)
+		return nil
+	case "Disabled":
+		return setDisabled(&c.ControlState, value)
+	}
+	if err := setTextAttribute(&c.Text, name, value); err != nil {
+		return err
+	}
+	// Make room for the box
+	c.SetFontSize(c.FontSize())
+	return nil
+}
+
+// GetAttribute returns Checked, which the user can change
+func (c *CheckBox) GetAttribute(name string) (components.Attribute, bool) {
+	if name == "Checked" {
+		return formatBool(c.checked), true
+	}
+	return "", false
+}
+
+func (c *CheckBox) String() string {
+	return fmt.Sprintf(`<CheckBox Text="%s" FontFace="%s" FontSize="%g" Color="%s" Checked="%t" Disabled="%t" %s />`,
+		escapeAttribute(c.Content()), escapeAttribute(c.FontFace()), c.FontSize(), utils.ToHexColor(c.Color()),
+		c.checked, c.Disabled(), formatStateColors(c.Colors))
+}
+
+// parseCheckAttributes applies the attributes shared by check boxes and radio buttons
+func parseCheckAttributes(box *CheckBox, list components.AttributeList) error {
+	if _, ok := list["VerticalAlign"]; !ok {
+		list["VerticalAlign"] = "Center"
+	}
+	if err := parseTextAttributes(&box.Text, list); err != nil {
+		return err
+	}
+	// Make room for the box, now that the font size is known
+	box.SetFontSize(box.FontSize())
+
+	if err := parseControlAttributes(&box.ControlState, list); err != nil {
+		return err
+	}
+
+	colors, err := parseStateColors(list, box.Colors)
+	if err != nil {
+		return err
+	}
+	box.Colors = colors
+
+	checked, err := list.Get("Checked", "false").Bool()
+	if err != nil {
+		return errors.New("Checked must be either true or false")
+	}
+	box.SetChecked(checked)
+	return nil
+}
+
+func makeCheckBox(list components.AttributeList) (components.Component, error) {
+	box := MakeCheckBox("")
+	if err := parseCheckAttributes(box, list); err != nil {
+		return nil, err
+	}
+	return box, nil
+}
@@ -1 +1,449 @@
 package builtin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hamcha/youi/components"
+)
+
+// TrackUnit is how a grid row or column is sized
+type TrackUnit int
+
+// Track units
+const (
+	// TrackPixels is a fixed size in pixels
+	TrackPixels TrackUnit = iota
+	// TrackStar is a fraction of the space left after all other tracks are sized
+	TrackStar
+	// TrackAuto is as big as the largest child in it
+	TrackAuto
+)
+
+// Track is the definition of a single grid row or column
+type Track struct {
+	Unit  TrackUnit
+	Value float32
+}
+
+func (t Track) String() string {
+	switch t.Unit {
+	case TrackStar:
+		if t.Value == 1 {
+			return "*"
+		}
+		return fmt.Sprintf("%g*", t.Value)
+	case TrackAuto:
+		return "Auto"
+	}
+	return fmt.Sprintf("%g", t.Value)
+}
+
+// ErrInvalidTrack means a track definition could not be parsed
+var ErrInvalidTrack = errors.New("tracks must be comma-separated pixel sizes (100), fractions (*, 2*) or Auto")
+
+// ParseTracks parses a comma-separated track list, eg. "Auto,*,2*,100"
+func ParseTracks(str string) ([]Track, error) {
+	var tracks []Track
+	for _, part := range strings.Split(str, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.EqualFold(part, "Auto"):
+			tracks = append(tracks, Track{Unit: TrackAuto})
+		case strings.HasSuffix(part, "*"):
+			weight := float64(1)
+			if part != "*" {
+				var err error
+				weight, err = strconv.ParseFloat(strings.TrimSuffix(part, "*"), 32)
+				if err != nil || weight < 0 {
+					return nil, ErrInvalidTrack
+				}
+			}
+			tracks = append(tracks, Track{Unit: TrackStar, Value: float32(weight)})
+		default:
+			size, err := strconv.ParseFloat(part, 32)
+			if err != nil || size < 0 {
+				return nil, ErrInvalidTrack
+			}
+			tracks = append(tracks, Track{Unit: TrackPixels, Value: float32(size)})
+		}
+	}
+	return tracks, nil
+}
+
+func tracksString(tracks []Track) string {
+	strs := make([]string, len(tracks))
+	for i, track := range tracks {
+		strs[i] = track.String()
+	}
+	return strings.Join(strs, ",")
+}
+
+// GridItem holds the cell settings of a single Grid child
+type GridItem struct {
+	Row, Column         int
+	RowSpan, ColumnSpan int
+	// Width and Height override the child's measured size when sizing Auto tracks
+	Width, Height float32
+}
+
+// DefaultGridItem returns the settings children have unless specified otherwise
+func DefaultGridItem() GridItem {
+	return GridItem{
+		RowSpan:    1,
+		ColumnSpan: 1,
+		Width:      Auto,
+		Height:     Auto,
+	}
+}
+
+// Grid is a container that places its children into cells defined by row and column tracks.
+// Children can span multiple cells, multiple children in the same cell overlap.
+type Grid struct {
+	components.Base
+
+	Rows      []Track
+	Columns   []Track
+	RowGap    float32
+	ColumnGap float32
+	Padding   components.Insets
+
+	items map[components.Component]*GridItem
+}
+
+// Item returns the cell settings of a child, so they can be changed
+func (g *Grid) Item(child components.Component) *GridItem {
+	if g.items == nil {
+		g.items = make(map[components.Component]*GridItem)
+	}
+	item, ok := g.items[child]
+	if !ok {
+		def := DefaultGridItem()
+		item = &def
+		g.items[child] = item
+	}
+	return item
+}
+
+// RemoveChild removes a child and forgets its cell settings
+func (g *Grid) RemoveChild(child components.Component) error {
+	if err := g.Base.RemoveChild(child); err != nil {
+		return err
+	}
+	delete(g.items, child)
+	return nil
+}
+
+// RemoveChildByIndex removes the ith child and forgets its cell settings
+func (g *Grid) RemoveChildByIndex(i int) error {
+	var child components.Component
+	if children := g.Children(); i >= 0 && i < len(children) {
+		child = children[i]
+	}
+	if err := g.Base.RemoveChildByIndex(i); err != nil {
+		return err
+	}
+	delete(g.items, child)
+	return nil
+}
+
+// SetChildSettings parses per-child settings (Row, Column, RowSpan, ColumnSpan, Width, Height)
+func (g *Grid) SetChildSettings(child components.Component, settings components.AttributeList) error {
+	item := g.Item(child)
+	for name, value := range settings {
+		var err error
+		switch name {
+		case "Row":
+			item.Row, err = value.Int()
+		case "Column":
+			item.Column, err = value.Int()
+		case "RowSpan":
+			item.RowSpan, err = value.Int()
+		case "ColumnSpan":
+			item.ColumnSpan, err = value.Int()
+		case "Width":
+			item.Width, err = value.Float32()
+		case "Height":
+			item.Height, err = value.Float32()
+		default:
+			return fmt.Errorf("unknown Grid setting \"%s\"", name)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for Grid.%s: %s", name, err.Error())
+		}
+	}
+	if item.Row < 0 || item.Column < 0 || item.RowSpan < 1 || item.ColumnSpan < 1 {
+		return errors.New("Grid.Row and Grid.Column must be positive, spans must be at least 1")
+	}
+	return nil
+}
+
+// cellRange returns the track range a child occupies, clamped to existing tracks
+func cellRange(start, span, count int) (int, int) {
+	if start >= count {
+		start = count - 1
+	}
+	end := start + span
+	if end > count {
+		end = count
+	}
+	return start, end
+}
+
+// trackChild is what track sizing needs to know about a child on one axis
+type trackChild struct {
+	start, span int
+	size        float32
+}
+
+// sizeTracks works out the pixel size of each track on one axis. When measuring, star tracks
+// are sized like Auto tracks, since there is no fixed space to split yet.
+func sizeTracks(tracks []Track, available, gap float32, children []trackChild, measuring bool) []float32 {
+	sizes := make([]float32, len(tracks))
+	available -= gap * float32(len(tracks)-1)
+	isAuto := func(track Track) bool {
+		return track.Unit == TrackAuto || (measuring && track.Unit == TrackStar)
+	}
+
+	// Fixed and auto tracks first, auto tracks are as big as the children that fit in them
+	for i, track := range tracks {
+		if track.Unit == TrackPixels {
+			sizes[i] = track.Value
+		} else if isAuto(track) {
+			for _, child := range children {
+				if child.start == i && child.span == 1 && child.size > sizes[i] {
+					sizes[i] = child.size
+				}
+			}
+		}
+	}
+
+	// Children spanning more tracks spread what doesn't fit over the auto tracks they cover
+	// (unless they cover star tracks, which take the space left anyway), smaller spans first
+	spanning := make([]trackChild, 0, len(children))
+	for _, child := range children {
+		if child.span > 1 {
+			spanning = append(spanning, child)
+		}
+	}
+	sort.SliceStable(spanning, func(i, j int) bool { return spanning[i].span < spanning[j].span })
+	for _, child := range spanning {
+		covered, autoCount := gap*float32(child.span-1), 0
+		hasStar := false
+		for i := child.start; i < child.start+child.span; i++ {
+			covered += sizes[i]
+			switch {
+			case isAuto(tracks[i]):
+				autoCount++
+			case tracks[i].Unit == TrackStar:
+				hasStar = true
+			}
+		}
+		if hasStar || autoCount == 0 || child.size <= covered {
+			continue
+		}
+		extra := (child.size - covered) / float32(autoCount)
+		for i := child.start; i < child.start+child.span; i++ {
+			if isAuto(tracks[i]) {
+				sizes[i] += extra
+			}
+		}
+	}
+
+	// Split what's left between star tracks
+	var used, totalStars float32
+	for i, track := range tracks {
+		if track.Unit == TrackStar && !measuring {
+			totalStars += track.Value
+		} else {
+			used += sizes[i]
+		}
+	}
+	free := available - used
+	if free > 0 && totalStars > 0 {
+		for i, track := range tracks {
+			if track.Unit == TrackStar {
+				sizes[i] = free * track.Value / totalStars
+			}
+		}
+	}
+
+	return sizes
+}
+
+// trackChildren measures all children and returns their position and size on both axes
+func (g *Grid) trackChildren(available components.Size, rowCount, columnCount int) (rows, columns []trackChild) {
+	for _, child := range g.Children() {
+		item := g.Item(child)
+		width, height := item.Width, item.Height
+		if width == Auto || height == Auto {
+			desired := child.Measure(available)
+			if width == Auto {
+				width = desired.Width
+			}
+			if height == Auto {
+				height = desired.Height
+			}
+		}
+
+		colStart, colEnd := cellRange(item.Column, item.ColumnSpan, columnCount)
+		rowStart, rowEnd := cellRange(item.Row, item.RowSpan, rowCount)
+		columns = append(columns, trackChild{colStart, colEnd - colStart, width})
+		rows = append(rows, trackChild{rowStart, rowEnd - rowStart, height})
+	}
+	return
+}
+
+// Measure returns the size of all tracks, with star tracks as big as their content
+func (g *Grid) Measure(available components.Size) components.Size {
+	rows, columns := g.tracks()
+	inner := components.Bounds{Size: available}.Shrink(g.Padding).Size
+	rowChildren, columnChildren := g.trackChildren(inner, len(rows), len(columns))
+
+	size := components.Size{
+		Width:  sum(sizeTracks(columns, inner.Width, g.ColumnGap, columnChildren, true)),
+		Height: sum(sizeTracks(rows, inner.Height, g.RowGap, rowChildren, true)),
+	}
+	size.Width += g.ColumnGap*float32(len(columns)-1) + g.Padding.Left + g.Padding.Right
+	size.Height += g.RowGap*float32(len(rows)-1) + g.Padding.Top + g.Padding.Bottom
+	return g.Constrain(size)
+}
+
+// Arrange sizes all tracks and puts each child in its cells
+func (g *Grid) Arrange(pixels components.Bounds) {
+	g.SetPixelBounds(pixels)
+
+	children := g.Children()
+	if len(children) == 0 {
+		return
+	}
+
+	rows, columns := g.tracks()
+	inner := pixels.Shrink(g.Padding)
+	rowChildren, columnChildren := g.trackChildren(inner.Size, len(rows), len(columns))
+
+	columnSizes := sizeTracks(columns, inner.Width, g.ColumnGap, columnChildren, false)
+	rowSizes := sizeTracks(rows, inner.Height, g.RowGap, rowChildren, false)
+
+	// Get where each track starts
+	columnStarts := trackStarts(columnSizes, inner.X, g.ColumnGap)
+	rowStarts := trackStarts(rowSizes, inner.Y, g.RowGap)
+
+	for i, child := range children {
+		col, row := columnChildren[i], rowChildren[i]
+		colEnd, rowEnd := col.start+col.span-1, row.start+row.span-1
+
+		size := components.Size{
+			Width:  columnStarts[colEnd] + columnSizes[colEnd] - columnStarts[col.start],
+			Height: rowStarts[rowEnd] + rowSizes[rowEnd] - rowStarts[row.start],
+		}
+		min, max := child.Constraints()
+		child.Arrange(components.Bounds{
+			Position: components.Position{X: columnStarts[col.start], Y: rowStarts[row.start]},
+			Size:     components.ConstrainSize(size, min, max),
+		})
+	}
+}
+
+func sum(values []float32) (total float32) {
+	for _, value := range values {
+		total += value
+	}
+	return
+}
+
+func (g *Grid) tracks() (rows, columns []Track) {
+	rows, columns = g.Rows, g.Columns
+	if len(rows) == 0 {
+		rows = []Track{{Unit: TrackStar, Value: 1}}
+	}
+	if len(columns) == 0 {
+		columns = []Track{{Unit: TrackStar, Value: 1}}
+	}
+	return
+}
+
+func trackStarts(sizes []float32, start, gap float32) []float32 {
+	starts := make([]float32, len(sizes))
+	for i, size := range sizes {
+		starts[i] = start
+		start += size + gap
+	}
+	return starts
+}
+
+func (g *Grid) childrenStr() (out string) {
+	for _, child := range g.Children() {
+		str := fmt.Sprint(child)
+		if item, ok := g.items[child]; ok {
+			str = addSettings(str, "Grid", item.settings())
+		}
+		out += indent(str) + "\n"
+	}
+	return
+}
+
+func (g GridItem) settings() (out []string) {
+	def := DefaultGridItem()
+	if g.Row != def.Row {
+		out = append(out, fmt.Sprintf("Row=\"%d\"", g.Row))
+	}
+	if g.Column != def.Column {
+		out = append(out, fmt.Sprintf("Column=\"%d\"", g.Column))
+	}
+	if g.RowSpan != def.RowSpan {
+		out = append(out, fmt.Sprintf("RowSpan=\"%d\"", g.RowSpan))
+	}
+	if g.ColumnSpan != def.ColumnSpan {
+		out = append(out, fmt.Sprintf("ColumnSpan=\"%d\"", g.ColumnSpan))
+	}
+	if g.Width != def.Width {
+		out = append(out, fmt.Sprintf("Width=\"%g\"", g.Width))
+	}
+	if g.Height != def.Height {
+		out = append(out, fmt.Sprintf("Height=\"%g\"", g.Height))
+	}
+	return
+}
+
+func (g *Grid) String() string {
+	rows, columns := g.tracks()
+	return fmt.Sprintf(`<Grid Rows="%s" Columns="%s" RowGap="%g" ColumnGap="%g" Padding="%s">
+%s</Grid>`,
+		tracksString(rows), tracksString(columns), g.RowGap, g.ColumnGap, g.Padding, g.childrenStr())
+}
+
+func makeGrid(list components.AttributeList) (components.Component, error) {
+	grid := &Grid{}
+
+	var err error
+	grid.Rows, err = ParseTracks(list.Get("Rows", "*").String())
+	if err != nil {
+		return nil, fmt.Errorf("Rows: %s", err.Error())
+	}
+
+	grid.Columns, err = ParseTracks(list.Get("Columns", "*").String())
+	if err != nil {
+		return nil, fmt.Errorf("Columns: %s", err.Error())
+	}
+
+	grid.RowGap, err = list.Get("RowGap", "0").Float32()
+	if err != nil {
+		return nil, errors.New("RowGap must be a number")
+	}
+
+	grid.ColumnGap, err = list.Get("ColumnGap", "0").Float32()
+	if err != nil {
+		return nil, errors.New("ColumnGap must be a number")
+	}
+
+	grid.Padding, err = list.Get("Padding", "0").Insets()
+	if err != nil {
+		return nil, errors.New("Padding must be one, two or four comma-separated numbers")
+	}
+
+	return grid, nil
+}
@@ -0,0 +1,109 @@
+package builtin
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hamcha/youi/components"
+)
+
+func TestParseTracks(t *testing.T) {
+	tracks, err := ParseTracks("Auto, *, 2*, 100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracksString(tracks) != "Auto,*,2*,100" {
+		t.Errorf("unexpected tracks: %s", tracksString(tracks))
+	}
+
+	if _, err := ParseTracks("1**"); err == nil {
+		t.Error("expected invalid track to fail")
+	}
+}
+
+func TestGridLayout(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{310, 210})
+
+	grid := &Grid{ColumnGap: 10, RowGap: 10}
+	grid.Columns, _ = ParseTracks("100,*,2*")
+	grid.Rows, _ = ParseTracks("Auto,*")
+
+	header := &components.Base{}
+	cell := &components.Base{}
+	grid.AppendChild(header)
+	grid.AppendChild(cell)
+	err := grid.SetChildSettings(header, components.AttributeList{"ColumnSpan": "3", "Height": "40"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = grid.SetChildSettings(cell, components.AttributeList{"Row": "1", "Column": "2", "Height": "500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AppendChild(grid)
+
+	root.Layout()
+
+	// Columns: 100, (310-20-100)/3 = 63.3, 126.7
+	if got := pixelBounds(header, root); got != image.Rect(0, 0, 310, 40) {
+		t.Errorf("header: expected full width auto row, got %v", got)
+	}
+	if got := pixelBounds(cell, root); got != image.Rect(183, 50, 310, 210) {
+		t.Errorf("cell: got %v", got)
+	}
+}
+
+func TestGridSpanningAutoTracks(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{300, 100})
+
+	grid := &Grid{ColumnGap: 10}
+	grid.Columns, _ = ParseTracks("Auto,Auto,*")
+	grid.Rows, _ = ParseTracks("Auto")
+
+	wide := &components.Base{}
+	narrow := &components.Base{}
+	second := &components.Base{}
+	settings := []components.AttributeList{
+		{"ColumnSpan": "2", "Width": "100", "Height": "20"},
+		{"Width": "30", "Height": "20"},
+		{"Column": "1", "Width": "10", "Height": "20"},
+	}
+	for i, child := range []components.Component{wide, narrow, second} {
+		grid.AppendChild(child)
+		if err := grid.SetChildSettings(child, settings[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root.AppendChild(grid)
+	root.Layout()
+
+	// Columns start at 30 and 10, the 50px the wide child still needs are split between them
+	if got := pixelBounds(wide, root); got != image.Rect(0, 0, 100, 20) {
+		t.Errorf("wide: expected to span 100px, got %v", got)
+	}
+	if got := pixelBounds(second, root); got != image.Rect(65, 0, 100, 20) {
+		t.Errorf("second: expected second column at 65px, got %v", got)
+	}
+}
+
+func TestGridRemoveChild(t *testing.T) {
+	grid := &Grid{}
+	first, second := &components.Base{}, &components.Base{}
+	grid.AppendChild(first)
+	grid.AppendChild(second)
+	grid.Item(first).Row = 1
+	grid.Item(second).Column = 1
+
+	// Settings of removed children are not kept around
+	if err := grid.RemoveChild(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := grid.RemoveChildByIndex(0); err != nil {
+		t.Fatal(err)
+	}
+	if len(grid.items) != 0 {
+		t.Errorf("expected no settings left, got %d", len(grid.items))
+	}
+}
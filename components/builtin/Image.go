@@ -5,19 +5,9 @@ import (
 
 	"github.com/hamcha/youi/components"
 	"github.com/hamcha/youi/loader"
-	"github.com/hamcha/youi/opengl"
+	"github.com/hamcha/youi/render"
 )
 
-const imageFragShader = `
-#version 330 core
-uniform sampler2D imgdata;
-in vec2 fragTexCoord;
-out vec4 color;
-void main() {
-	color = texture(imgdata, fragTexCoord);
-}
-` + "\x00"
-
 // Image is a simple box that can contain an image or any sort of drawable surface
 type Image struct {
 	components.Drawable
@@ -25,7 +15,6 @@ type Image struct {
 	src          string
 	content      *image.RGBA
 	dirtyContent bool
-	texture      *opengl.Texture
 }
 
 func (i *Image) SetPath(src string) error {
@@ -50,34 +39,48 @@ func (i *Image) ShouldDraw() bool {
 	return i.dirtyContent || i.Drawable.ShouldDraw()
 }
 
-func (i *Image) Draw() {
-	if i.Mesh == nil {
-		shader := opengl.DefaultShader()
-		err := shader.SetFragmentSource(imageFragShader)
-		if err != nil {
-			panic(err)
-		}
-		i.Mesh = opengl.MakeQuad(shader)
-	}
+func (i *Image) Draw(r render.Renderer) {
 	if i.dirtyContent {
-		i.texture = opengl.MakeTexture(i.content, opengl.TextureOptions{
-			WrapS:     opengl.TextureWrapClamp,
-			WrapR:     opengl.TextureWrapClamp,
-			MinFilter: opengl.TextureFilterLinear,
-			MagFilter: opengl.TextureFilterLinear,
-		})
-		i.Mesh.Shader.GetUniform("imgdata").Set(i.texture)
+		i.Texture = nil
+		if i.content != nil {
+			i.Texture = r.MakeTexture(i.content)
+		}
 	}
 
-	i.Drawable.Draw()
+	i.Drawable.Draw(r)
 
 	i.ClearFlags()
 }
 
+// Measure returns the image size in pixels
+func (i *Image) Measure(available components.Size) components.Size {
+	if i.content == nil {
+		return i.Constrain(components.Size{})
+	}
+	size := i.content.Rect.Size()
+	return i.Constrain(components.Size{
+		Width:  float32(size.X),
+		Height: float32(size.Y),
+	})
+}
+
 func (i *Image) ClearFlags() {
 	i.dirtyContent = false
 }
 
+// SetAttribute changes Path, for bindings
+func (i *Image) SetAttribute(name string, value components.Attribute) error {
+	if name != "Path" {
+		return errAttributeNotSettable(name)
+	}
+	if value == "" {
+		i.src = ""
+		i.SetImage(nil)
+		return nil
+	}
+	return i.SetPath(value.String())
+}
+
 func (i *Image) String() string {
 	return "<Image Path=\"" + i.src + "\" />"
 }
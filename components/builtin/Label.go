@@ -1,18 +1,24 @@
 package builtin
 
-import "github.com/hamcha/youi/components"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/font"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
 
 // Label is a drawable text label
 type Label struct {
-	components.Base
 	components.Text
 }
 
 // Draw draws the label on screen
-func (l *Label) Draw() {
-	//TODO
-
-	l.Text.Draw()
+func (l *Label) Draw(r render.Renderer) {
+	l.Text.Draw(r)
 	l.Text.ClearFlags()
 }
 
@@ -21,12 +27,226 @@ func (l *Label) ShouldDraw() bool {
 	return l.Text.ShouldDraw()
 }
 
+var textAlignNames = map[components.TextAlign]string{
+	components.TextAlignLeft:    "Left",
+	components.TextAlignCenter:  "Center",
+	components.TextAlignRight:   "Right",
+	components.TextAlignJustify: "Justify",
+}
+
+var verticalAlignNames = map[components.VerticalAlign]string{
+	components.VerticalAlignTop:    "Top",
+	components.VerticalAlignCenter: "Center",
+	components.VerticalAlignBottom: "Bottom",
+}
+
+var textDirectionNames = map[font.Direction]string{
+	font.DirectionAuto: "Auto",
+	font.LeftToRight:   "LeftToRight",
+	font.RightToLeft:   "RightToLeft",
+}
+
+func parseTextAlign(str string) (components.TextAlign, error) {
+	for align, name := range textAlignNames {
+		if strings.EqualFold(name, str) {
+			return align, nil
+		}
+	}
+	return components.TextAlignLeft, errors.New("HorizontalAlign must be one of Left, Center, Right, Justify")
+}
+
+func parseVerticalAlign(str string) (components.VerticalAlign, error) {
+	for align, name := range verticalAlignNames {
+		if strings.EqualFold(name, str) {
+			return align, nil
+		}
+	}
+	return components.VerticalAlignTop, errors.New("VerticalAlign must be one of Top, Center, Bottom")
+}
+
+func parseTextDirection(str string) (font.Direction, error) {
+	for direction, name := range textDirectionNames {
+		if strings.EqualFold(name, str) {
+			return direction, nil
+		}
+	}
+	return font.DirectionAuto, errors.New("Direction must be one of Auto, LeftToRight, RightToLeft")
+}
+
 func (l *Label) String() string {
-	//TODO Add attributes
-	return "<Label />"
+	attributes := fmt.Sprintf(`FontFace="%s" FontSize="%g" Color="%s" HorizontalAlign="%s" VerticalAlign="%s" Wrap="%t" LineHeight="%g" Direction="%s" Padding="%s"`,
+		escapeAttribute(l.FontFace()), l.FontSize(), utils.ToHexColor(l.Color()),
+		textAlignNames[l.Align()], verticalAlignNames[l.VerticalAlign()], l.Wrap(), l.LineHeight(),
+		textDirectionNames[l.Direction()], l.Padding())
+
+	// Styled text is written as inline content
+	if runs := l.Runs(); runs != nil {
+		return fmt.Sprintf(`<Label %s>%s</Label>`, attributes, formatRuns(runs))
+	}
+	return fmt.Sprintf(`<Label Text="%s" %s />`, escapeAttribute(l.Content()), attributes)
+}
+
+// formatRuns writes styled runs of text as YUML inline content
+func formatRuns(runs []components.TextRun) string {
+	out := ""
+	for _, run := range runs {
+		if run.Text == "\n" {
+			out += "<LineBreak />"
+			continue
+		}
+
+		style := run.Style
+		attributes := ""
+		if style.FontFace != "" {
+			attributes += fmt.Sprintf(` FontFace="%s"`, escapeAttribute(style.FontFace))
+		}
+		if style.FontSize > 0 {
+			attributes += fmt.Sprintf(` FontSize="%g"`, style.FontSize)
+		}
+		if style.Color != nil {
+			attributes += fmt.Sprintf(` Color="%s"`, utils.ToHexColor(style.Color))
+		}
+		for _, flag := range []struct {
+			name  string
+			value bool
+		}{{"Bold", style.Bold}, {"Italic", style.Italic}, {"Underline", style.Underline}, {"Strikethrough", style.Strikethrough}} {
+			if flag.value {
+				attributes += fmt.Sprintf(` %s="true"`, flag.name)
+			}
+		}
+
+		if attributes == "" {
+			out += escapeAttribute(run.Text)
+		} else {
+			out += fmt.Sprintf("<Span%s>%s</Span>", attributes, escapeAttribute(run.Text))
+		}
+	}
+	return out
+}
+
+// textAttributes are the attributes shared by all text components, in the order they are
+// applied, with their default values
+var textAttributes = []struct {
+	name  string
+	value components.Attribute
+}{
+	{"Text", ""},
+	{"FontFace", ""},
+	{"FontSize", "0"},
+	{"Color", ""},
+	{"HorizontalAlign", "Left"},
+	{"VerticalAlign", "Top"},
+	{"Wrap", "false"},
+	{"LineHeight", "1"},
+	{"Padding", ""},
+	{"Direction", "Auto"},
+}
+
+// parseTextAttributes applies the attributes shared by all text components
+func parseTextAttributes(text *components.Text, list components.AttributeList) error {
+	for _, attr := range textAttributes {
+		if err := setTextAttribute(text, attr.name, list.Get(attr.name, attr.value.String())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTextAttribute changes one of the attributes shared by all text components
+func setTextAttribute(text *components.Text, name string, value components.Attribute) error {
+	switch name {
+	case "Text":
+		text.SetText(value.String())
+
+	case "FontFace":
+		if value != "" {
+			// Load font now, so we can report errors
+			if _, err := font.LoadFont(value.String()); err != nil {
+				return fmt.Errorf("could not load font \"%s\": %s", value, err.Error())
+			}
+		}
+		text.SetFontFace(value.String())
+
+	case "FontSize":
+		size, err := value.Float32()
+		if err != nil || size < 0 {
+			return errors.New("FontSize must be a positive number")
+		}
+		text.SetFontSize(float64(size))
+
+	case "Color":
+		if value == "" {
+			text.SetColor(nil)
+			break
+		}
+		hcol, err := value.Color()
+		if err != nil {
+			return err
+		}
+		text.SetColor(hcol)
+
+	case "HorizontalAlign":
+		align, err := parseTextAlign(value.String())
+		if err != nil {
+			return err
+		}
+		text.SetAlign(align)
+
+	case "VerticalAlign":
+		valign, err := parseVerticalAlign(value.String())
+		if err != nil {
+			return err
+		}
+		text.SetVerticalAlign(valign)
+
+	case "Wrap":
+		wrap, err := value.Bool()
+		if err != nil {
+			return errors.New("Wrap must be either true or false")
+		}
+		text.SetWrap(wrap)
+
+	case "LineHeight":
+		lineHeight, err := value.Float32()
+		if err != nil || lineHeight <= 0 {
+			return errors.New("LineHeight must be a positive number")
+		}
+		text.SetLineHeight(lineHeight)
+
+	case "Padding":
+		if value == "" {
+			text.SetPadding(components.Insets{})
+			break
+		}
+		insets, err := value.Insets()
+		if err != nil {
+			return err
+		}
+		text.SetPadding(insets)
+
+	case "Direction":
+		direction, err := parseTextDirection(value.String())
+		if err != nil {
+			return err
+		}
+		text.SetDirection(direction)
+
+	default:
+		return errAttributeNotSettable(name)
+	}
+	return nil
+}
+
+// SetAttribute changes a text attribute, for bindings
+func (l *Label) SetAttribute(name string, value components.Attribute) error {
+	return setTextAttribute(&l.Text, name, value)
 }
 
 func makeLabel(list components.AttributeList) (components.Component, error) {
-	//TODO Parse attributes
-	return &Label{}, nil
+	label := &Label{}
+	err := parseTextAttributes(&label.Text, list)
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
 }
@@ -0,0 +1,83 @@
+package builtin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/font"
+	"github.com/hamcha/youi/utils"
+	"github.com/hamcha/youi/yuml"
+)
+
+// checkLabel makes sure a label has the attributes set in TestLabelRoundTrip
+func checkLabel(t *testing.T, label *Label) {
+	if label.Content() != "Hello \"world\"" {
+		t.Errorf("Expected text %q, got %q", "Hello \"world\"", label.Content())
+	}
+	if label.FontFace() != "default" {
+		t.Errorf("Expected font face default, got %q", label.FontFace())
+	}
+	if label.FontSize() != 18.5 {
+		t.Errorf("Expected font size 18.5, got %g", label.FontSize())
+	}
+	if color := utils.ToHexColor(label.Color()); color != 0x336699ff {
+		t.Errorf("Expected color #336699ff, got %s", color)
+	}
+	if label.Align() != components.TextAlignRight {
+		t.Errorf("Expected right alignment, got %s", textAlignNames[label.Align()])
+	}
+	if label.VerticalAlign() != components.VerticalAlignBottom {
+		t.Errorf("Expected bottom alignment, got %s", verticalAlignNames[label.VerticalAlign()])
+	}
+	if !label.Wrap() {
+		t.Error("Expected label to wrap")
+	}
+}
+
+func TestLabelRoundTrip(t *testing.T) {
+	// Make sure the default font is there for FontFace
+	font.DefaultFont()
+
+	component, err := makeLabel(components.AttributeList{
+		"Text":            "Hello \"world\"",
+		"FontFace":        "default",
+		"FontSize":        "18.5",
+		"Color":           "#336699",
+		"HorizontalAlign": "Right",
+		"VerticalAlign":   "Bottom",
+		"Wrap":            "true",
+	})
+	if err != nil {
+		t.Fatalf("Could not make label: %s", err.Error())
+	}
+	label := component.(*Label)
+	checkLabel(t, label)
+
+	// What String() writes must give back the same label
+	element, err := yuml.ParseYUML(strings.NewReader(label.String()))
+	if err != nil {
+		t.Fatalf("Could not parse %s: %s", label.String(), err.Error())
+	}
+	attributes := make(components.AttributeList)
+	for _, attr := range element.Attributes {
+		attributes[attr.Name.Local] = components.Attribute(attr.Value)
+	}
+	component, err = makeLabel(attributes)
+	if err != nil {
+		t.Fatalf("Could not make label from %s: %s", label.String(), err.Error())
+	}
+	checkLabel(t, component.(*Label))
+}
+
+func TestLabelInvalidAttributes(t *testing.T) {
+	tests := []components.AttributeList{
+		{"HorizontalAlign": "Middle"},
+		{"FontSize": "-4"},
+	}
+	for _, attributes := range tests {
+		if _, err := makeLabel(attributes); err == nil {
+			t.Errorf("Expected an error for %v", attributes)
+		}
+	}
+}
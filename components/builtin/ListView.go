@@ -0,0 +1,676 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// ListSource provides the items shown by a ListView
+type ListSource interface {
+	// Count returns how many items there are
+	Count() int
+	// ItemAt returns an item, which is given to ListView.BindItem
+	ItemAt(index int) interface{}
+}
+
+// StringList is a ListSource over a slice of strings
+type StringList []string
+
+// Count returns the length of the slice
+func (s StringList) Count() int {
+	return len(s)
+}
+
+// ItemAt returns the string at an index
+func (s StringList) ItemAt(index int) interface{} {
+	return s[index]
+}
+
+// SelectionMode is how many items of a list can be selected at once
+type SelectionMode int
+
+// Selection modes
+const (
+	SelectNone SelectionMode = iota
+	SelectSingle
+	SelectMultiple
+)
+
+// ErrListNoTemplate means the ListView has no template to make rows from
+var ErrListNoTemplate = errors.New("ListView has no item template")
+
+// ListView is a scrolling list of items from a ListSource. Only the rows that are visible
+// are made (from a template), and rows that scroll out of view are reused for the ones
+// coming in, so lists can have any amount of items. All rows are as tall.
+type ListView struct {
+	ScrollView
+
+	// ItemHeight is the height of each row in pixels, 0 means the height of the first row
+	ItemHeight float32
+	// Selection is how many items can be selected at once
+	Selection SelectionMode
+	// SelectionColor is the background of selected rows
+	SelectionColor color.Color
+	// BindItem shows an item in a row. The default one makes the item the data context of
+	// rows with bindings, and sets the text of the others to the item formatted as string.
+	BindItem func(row components.Component, index int, item interface{})
+	// OnSelectionChange is called when items are selected or deselected
+	OnSelectionChange func()
+
+	source   ListSource
+	template components.Template
+
+	// rows holds the rows in use by item index, free holds the ones that can be reused
+	rows map[int]components.Component
+	free []components.Component
+
+	// rowHeight is the height used for rows, measured if ItemHeight is not set
+	rowHeight float32
+
+	selected map[int]bool
+	// current is the item keyboard navigation starts from, anchor is where range selections
+	// (with shift) start from
+	current, anchor int
+	focused         bool
+
+	// focusRing is the color of the outline drawn while focused, see SetFocusRingColor
+	focusRing    color.Color
+	hasFocusRing bool
+}
+
+// MakeListView creates an empty list, it needs a template before it can show anything
+func MakeListView() *ListView {
+	list := &ListView{
+		Selection:      SelectSingle,
+		SelectionColor: DefaultSelectionColor,
+		rows:           make(map[int]components.Component),
+		selected:       make(map[int]bool),
+		current:        -1,
+		anchor:         -1,
+	}
+	list.ScrollView.init()
+	list.DragToScroll = false
+	list.AddEventListener(components.EventMouseDown, list.onMouseDown)
+	list.AddEventListener(components.EventKeyDown, list.onKeyDown)
+	return list
+}
+
+// SetTemplate sets how rows are made, all rows are made again
+func (l *ListView) SetTemplate(template components.Template) error {
+	l.template = template
+	l.rowHeight = 0
+	l.clearRows()
+	return nil
+}
+
+// SetSource replaces the items and clears the selection
+func (l *ListView) SetSource(source ListSource) {
+	l.source = source
+	l.selected = make(map[int]bool)
+	l.current, l.anchor = -1, -1
+	l.Refresh()
+	l.SetScrollOffset(0, 0)
+}
+
+// Source returns the items in the list
+func (l *ListView) Source() ListSource {
+	return l.source
+}
+
+// Count returns how many items there are
+func (l *ListView) Count() int {
+	if l.source == nil {
+		return 0
+	}
+	return l.source.Count()
+}
+
+// Refresh binds visible rows again, call it when items change. Selected items that are not
+// there anymore are deselected.
+func (l *ListView) Refresh() {
+	count := l.Count()
+	changed := false
+	for index := range l.selected {
+		if index >= count {
+			delete(l.selected, index)
+			changed = true
+		}
+	}
+	if l.current >= count {
+		l.current = count - 1
+	}
+	if l.anchor >= count {
+		l.anchor = count - 1
+	}
+	for index, row := range l.rows {
+		if index >= count {
+			l.recycle(index)
+			continue
+		}
+		l.bind(row, index)
+	}
+	l.SetRedraw()
+	if changed {
+		l.selectionChanged()
+	}
+}
+
+// clearRows throws away all rows, eg. when the template changes
+func (l *ListView) clearRows() {
+	for index := range l.rows {
+		l.recycle(index)
+	}
+	l.Destroy()
+}
+
+// Destroy frees the rows kept for later, the others are children and get destroyed with
+// the rest of the tree
+func (l *ListView) Destroy() {
+	for _, row := range l.free {
+		components.Destroy(row)
+	}
+	l.free = nil
+}
+
+// recycle takes the row of an item out of the list and keeps it for later
+func (l *ListView) recycle(index int) {
+	row := l.rows[index]
+	delete(l.rows, index)
+	l.RemoveChild(row)
+	// Until it's bound to another item, it shouldn't follow the old one
+	components.Unbind(row)
+	l.free = append(l.free, row)
+}
+
+// makeRow returns a row to show an item in, reusing one if possible
+func (l *ListView) makeRow(index int) (components.Component, error) {
+	var row components.Component
+	if len(l.free) > 0 {
+		row = l.free[len(l.free)-1]
+		l.free = l.free[:len(l.free)-1]
+	} else {
+		if l.template == nil {
+			return nil, ErrListNoTemplate
+		}
+		var err error
+		if row, err = l.template(); err != nil {
+			return nil, err
+		}
+	}
+	l.bind(row, index)
+	return row, nil
+}
+
+func (l *ListView) bind(row components.Component, index int) {
+	item := l.source.ItemAt(index)
+	if l.BindItem != nil {
+		l.BindItem(row, index, item)
+		return
+	}
+	if components.HasBindings(row) {
+		row.SetDataContext(item)
+		return
+	}
+	if text, ok := row.(interface{ SetText(string) }); ok {
+		text.SetText(fmt.Sprint(item))
+	}
+}
+
+// RowAt returns the row showing an item, or nil if the item is not visible
+func (l *ListView) RowAt(index int) components.Component {
+	return l.rows[index]
+}
+
+// IndexAt returns the index of the item under a point (relative to the root, like event
+// positions), or -1 if there is none
+func (l *ListView) IndexAt(pos components.Position) int {
+	if l.rowHeight <= 0 {
+		return -1
+	}
+	pixels := l.toPixels(pos)
+	bounds := l.PixelBounds()
+	if !bounds.Contains(pixels) {
+		return -1
+	}
+	index := int((pixels.Y - bounds.Y + l.offset.Y) / l.rowHeight)
+	if index < 0 || index >= l.Count() {
+		return -1
+	}
+	return index
+}
+
+// ScrollToIndex scrolls as little as possible to show an item
+func (l *ListView) ScrollToIndex(index int) {
+	l.ScrollIntoView(components.Bounds{
+		Position: components.Position{X: l.offset.X, Y: float32(index) * l.rowHeight},
+		Size:     components.Size{Height: l.rowHeight},
+	})
+}
+
+// measureRowHeight returns the height of rows, measuring a row if ItemHeight is not set
+func (l *ListView) measureRowHeight(width float32) float32 {
+	if l.ItemHeight > 0 {
+		return l.ItemHeight
+	}
+	if l.rowHeight > 0 || l.Count() == 0 {
+		return l.rowHeight
+	}
+	row, err := l.makeRow(0)
+	if err != nil {
+		return 0
+	}
+	l.free = append(l.free, row)
+	return row.Measure(components.Size{Width: width, Height: components.Unbounded}).Height
+}
+
+// Measure asks for as much space as all rows (up to what's available)
+func (l *ListView) Measure(available components.Size) components.Size {
+	l.rowHeight = l.measureRowHeight(available.Width)
+	l.content = components.Size{Width: available.Width, Height: float32(l.Count()) * l.rowHeight}
+	if available.Width >= components.Unbounded {
+		l.content.Width = 0
+	}
+
+	size := l.content
+	if size.Height > available.Height {
+		size.Height = available.Height
+	}
+	return l.Constrain(size)
+}
+
+// Arrange makes the rows that are visible, reusing the ones that are not anymore
+func (l *ListView) Arrange(pixels components.Bounds) {
+	l.SetPixelBounds(pixels)
+	l.viewport = pixels.Size
+	if l.content.Width < l.viewport.Width {
+		l.content.Width = l.viewport.Width
+	}
+	l.content.Height = float32(l.Count()) * l.rowHeight
+	if l.content.Height < l.viewport.Height {
+		l.content.Height = l.viewport.Height
+	}
+	l.SetScrollOffset(l.offset.X, l.offset.Y)
+
+	// Find visible items
+	first, last := 0, 0
+	if l.rowHeight > 0 {
+		first = int(l.offset.Y / l.rowHeight)
+		last = int(math.Ceil(float64((l.offset.Y + l.viewport.Height) / l.rowHeight)))
+		if count := l.Count(); last > count {
+			last = count
+		}
+	}
+
+	for index := range l.rows {
+		if index < first || index >= last {
+			l.recycle(index)
+		}
+	}
+	for index := first; index < last; index++ {
+		row, ok := l.rows[index]
+		if !ok {
+			var err error
+			if row, err = l.makeRow(index); err != nil {
+				continue
+			}
+			l.rows[index] = row
+			l.AppendChild(row)
+		}
+		row.Arrange(components.Bounds{
+			Position: components.Position{X: pixels.X - l.offset.X, Y: pixels.Y - l.offset.Y + float32(index)*l.rowHeight},
+			Size:     components.Size{Width: l.content.Width, Height: l.rowHeight},
+		})
+	}
+}
+
+// IsSelected returns whether an item is selected
+func (l *ListView) IsSelected(index int) bool {
+	return l.selected[index]
+}
+
+// listViewState is what a ListView keeps when its tree is made again
+type listViewState struct {
+	offset          components.Position
+	selected        map[int]bool
+	current, anchor int
+}
+
+// State returns the scroll offset and selection, see components.StateHolder
+func (l *ListView) State() interface{} {
+	selected := make(map[int]bool, len(l.selected))
+	for index := range l.selected {
+		selected[index] = true
+	}
+	return listViewState{l.offset, selected, l.current, l.anchor}
+}
+
+// SetState restores the scroll offset and selection, without calling OnSelectionChange.
+// Setting a source afterwards clears them.
+func (l *ListView) SetState(state interface{}) {
+	if state, ok := state.(listViewState); ok {
+		l.offset = state.offset
+		l.selected, l.current, l.anchor = state.selected, state.current, state.anchor
+		l.SetRedraw()
+	}
+}
+
+// SelectedIndex returns the first selected item, or -1 if none is
+func (l *ListView) SelectedIndex() int {
+	indices := l.SelectedIndices()
+	if len(indices) == 0 {
+		return -1
+	}
+	return indices[0]
+}
+
+// SelectedIndices returns all selected items, in order
+func (l *ListView) SelectedIndices() []int {
+	indices := make([]int, 0, len(l.selected))
+	for index := range l.selected {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// Select selects only one item (or none, with -1)
+func (l *ListView) Select(index int) {
+	if l.Selection == SelectNone || index >= l.Count() {
+		return
+	}
+	if index < 0 {
+		l.ClearSelection()
+		return
+	}
+	if len(l.selected) == 1 && l.selected[index] {
+		return
+	}
+	l.selected = map[int]bool{index: true}
+	l.current, l.anchor = index, index
+	l.selectionChanged()
+}
+
+// SetSelected selects or deselects an item, in addition to the ones already selected if
+// multiple items can be
+func (l *ListView) SetSelected(index int, selected bool) {
+	if l.Selection == SelectNone || index < 0 || index >= l.Count() || l.selected[index] == selected {
+		return
+	}
+	if selected && l.Selection == SelectSingle {
+		l.selected = make(map[int]bool)
+	}
+	if selected {
+		l.selected[index] = true
+	} else {
+		delete(l.selected, index)
+	}
+	l.current, l.anchor = index, index
+	l.selectionChanged()
+}
+
+// ClearSelection deselects all items
+func (l *ListView) ClearSelection() {
+	if len(l.selected) == 0 {
+		return
+	}
+	l.selected = make(map[int]bool)
+	l.selectionChanged()
+}
+
+// selectRange selects the items between the anchor and an index
+func (l *ListView) selectRange(index int) {
+	from, to := l.anchor, index
+	if from < 0 {
+		from = index
+	}
+	if from > to {
+		from, to = to, from
+	}
+	l.selected = make(map[int]bool)
+	for i := from; i <= to; i++ {
+		l.selected[i] = true
+	}
+	l.current = index
+	l.selectionChanged()
+}
+
+func (l *ListView) selectionChanged() {
+	l.SetRedraw()
+	if l.OnSelectionChange != nil {
+		l.OnSelectionChange()
+	}
+	notifyChange(l)
+}
+
+// SetAttribute changes SelectedIndex or ItemHeight, for bindings
+func (l *ListView) SetAttribute(name string, value components.Attribute) error {
+	switch name {
+	case "SelectedIndex":
+		index, err := value.Int()
+		if err != nil {
+			return errors.New("SelectedIndex must be an integer")
+		}
+		if index != l.SelectedIndex() {
+			l.Select(index)
+		}
+	case "ItemHeight":
+		height, err := value.Float32()
+		if err != nil || height < 0 {
+			return errors.New("ItemHeight must be a positive number")
+		}
+		l.ItemHeight = height
+		l.rowHeight = 0
+	default:
+		return errAttributeNotSettable(name)
+	}
+	return nil
+}
+
+// GetAttribute returns SelectedIndex, which the user can change
+func (l *ListView) GetAttribute(name string) (components.Attribute, bool) {
+	if name == "SelectedIndex" {
+		return components.Attribute(strconv.Itoa(l.SelectedIndex())), true
+	}
+	return "", false
+}
+
+// SetFocused sets whether the list gets arrow keys
+func (l *ListView) SetFocused(focused bool) {
+	if l.focused != focused {
+		l.focused = focused
+		l.SetRedraw()
+	}
+}
+
+// SetFocusRingColor sets the color of the outline drawn around the list and the current
+// item while focused, nil means no outline. Forms set it to their FocusRingColor.
+func (l *ListView) SetFocusRingColor(col color.Color) {
+	if l.hasFocusRing && l.focusRing == col {
+		return
+	}
+	l.focusRing, l.hasFocusRing = col, true
+	l.SetRedraw()
+}
+
+// FocusRingColor returns the color of the focus outline, components.DefaultFocusRingColor
+// unless SetFocusRingColor was called
+func (l *ListView) FocusRingColor() color.Color {
+	if !l.hasFocusRing {
+		return components.DefaultFocusRingColor
+	}
+	return l.focusRing
+}
+
+func (l *ListView) onMouseDown(ev *components.Event) {
+	if ev.Button != input.MouseButtonLeft || l.dragging {
+		return
+	}
+	index := l.IndexAt(ev.Position)
+	if index < 0 {
+		return
+	}
+	l.pick(index, ev.Modifiers)
+}
+
+// pick selects an item clicked or moved to with the keyboard, control toggles it and shift
+// selects a range when multiple items can be selected
+func (l *ListView) pick(index int, mods input.Modifier) {
+	switch {
+	case l.Selection == SelectMultiple && mods.Has(input.ModShift):
+		l.selectRange(index)
+	case l.Selection == SelectMultiple && (mods.Has(input.ModControl) || mods.Has(input.ModSuper)):
+		l.SetSelected(index, !l.selected[index])
+	default:
+		l.Select(index)
+	}
+	l.current = index
+	l.ScrollToIndex(index)
+}
+
+func (l *ListView) onKeyDown(ev *components.Event) {
+	count := l.Count()
+	if !l.focused || count == 0 {
+		return
+	}
+	page := 1
+	if l.rowHeight > 0 {
+		page = int(l.viewport.Height / l.rowHeight)
+	}
+	index := l.current
+	switch ev.Key {
+	case input.KeyUp:
+		index--
+	case input.KeyDown:
+		index++
+	case input.KeyPageUp:
+		index -= page
+	case input.KeyPageDown:
+		index += page
+	case input.KeyHome:
+		index = 0
+	case input.KeyEnd:
+		index = count - 1
+	default:
+		return
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index >= count {
+		index = count - 1
+	}
+	// Control only moves without selecting
+	if ev.Modifiers.Has(input.ModControl) && !ev.Modifiers.Has(input.ModShift) {
+		l.current = index
+		l.ScrollToIndex(index)
+		l.SetRedraw()
+	} else {
+		l.pick(index, ev.Modifiers&input.ModShift)
+	}
+	ev.StopPropagation()
+}
+
+// Draw draws the selected rows' background, the rows and the scroll bars, then the focus
+// outline around the list and the current item
+func (l *ListView) Draw(r render.Renderer) {
+	if len(l.selected) > 0 && l.rowHeight > 0 {
+		r.PushClip(l.Bounds().Rect())
+		for index := range l.rows {
+			if l.selected[index] {
+				r.FillRect(l.rowRect(index), l.SelectionColor)
+			}
+		}
+		r.PopClip()
+	}
+	l.ScrollView.Draw(r)
+
+	ring := l.FocusRingColor()
+	if !l.focused || ring == nil {
+		return
+	}
+	render.DrawOutline(r, l.Bounds().Rect(), ring, components.FocusRingWidth)
+	if _, visible := l.rows[l.current]; visible && l.rowHeight > 0 {
+		r.PushClip(l.Bounds().Rect())
+		render.DrawOutline(r, l.rowRect(l.current), ring, 1)
+		r.PopClip()
+	}
+}
+
+// rowRect returns the rectangle taken by the row of an item, relative to the root
+func (l *ListView) rowRect(index int) render.Rect {
+	bounds := l.PixelBounds()
+	row := components.Bounds{
+		Position: components.Position{X: bounds.X, Y: bounds.Y - l.offset.Y + float32(index)*l.rowHeight},
+		Size:     components.Size{Width: bounds.Width, Height: l.rowHeight},
+	}
+	return row.Scale(l.Root().Bounds().Size.Inverse()).Rect()
+}
+
+func (l *ListView) String() string {
+	return fmt.Sprintf("<ListView ItemHeight=\"%g\" Selection=\"%s\" SelectionColor=\"%s\" ScrollStep=\"%g\" BarColor=\"%s\" ThumbColor=\"%s\"></ListView>",
+		l.ItemHeight, selectionModeNames[l.Selection], utils.ToHexColor(l.SelectionColor), l.ScrollStep, utils.ToHexColor(l.BarColor), utils.ToHexColor(l.ThumbColor))
+}
+
+var selectionModeNames = map[SelectionMode]string{
+	SelectNone:     "None",
+	SelectSingle:   "Single",
+	SelectMultiple: "Multiple",
+}
+
+func parseSelectionMode(value string) (SelectionMode, error) {
+	for mode, name := range selectionModeNames {
+		if strings.EqualFold(name, value) {
+			return mode, nil
+		}
+	}
+	return SelectNone, errors.New("Selection must be one of None, Single, Multiple")
+}
+
+func makeListView(list components.AttributeList) (components.Component, error) {
+	view := MakeListView()
+
+	height, err := list.Get("ItemHeight", "0").Float32()
+	if err != nil || height < 0 {
+		return nil, errors.New("ItemHeight must be a positive number")
+	}
+	view.ItemHeight = height
+
+	if attr, ok := list["Selection"]; ok {
+		if view.Selection, err = parseSelectionMode(attr.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	step, err := list.Get("ScrollStep", fmt.Sprint(DefaultScrollStep)).Float32()
+	if err != nil || step <= 0 {
+		return nil, errors.New("ScrollStep must be a positive number")
+	}
+	view.ScrollStep = step
+
+	if attr, ok := list["DragToScroll"]; ok {
+		if view.DragToScroll, err = attr.Bool(); err != nil {
+			return nil, errors.New("DragToScroll must be either true or false")
+		}
+	}
+
+	if view.SelectionColor, err = parseColor(list, "SelectionColor", view.SelectionColor); err != nil {
+		return nil, err
+	}
+	if view.BarColor, err = parseColor(list, "BarColor", view.BarColor); err != nil {
+		return nil, err
+	}
+	if view.ThumbColor, err = parseColor(list, "ThumbColor", view.ThumbColor); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
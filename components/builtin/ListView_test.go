@@ -0,0 +1,232 @@
+package builtin
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+	"github.com/hamcha/youi/software"
+)
+
+// countSource is a ListSource of numbered lines
+type countSource int
+
+func (c countSource) Count() int                   { return int(c) }
+func (c countSource) ItemAt(index int) interface{} { return fmt.Sprintf("line %d", index) }
+
+func TestListViewRecycling(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{200, 100})
+
+	made := 0
+	list := MakeListView()
+	list.ItemHeight = 10
+	list.SetTemplate(func() (components.Component, error) {
+		made++
+		return &components.Base{}, nil
+	})
+	bound := make(map[components.Component]int)
+	list.BindItem = func(row components.Component, index int, item interface{}) {
+		bound[row] = index
+	}
+	list.SetSource(countSource(100000))
+	root.AppendChild(list)
+	root.Layout()
+
+	if len(list.Children()) != 10 || made != 10 {
+		t.Fatalf("expected 10 rows to be made, got %d (%d children)", made, len(list.Children()))
+	}
+
+	// Scrolling half a row shows one more, scrolling further reuses rows
+	list.ScrollBy(0, 5)
+	root.Layout()
+	list.ScrollBy(0, 50000)
+	root.Layout()
+	if made != 11 {
+		t.Fatalf("expected rows to be reused, %d were made", made)
+	}
+	row := list.RowAt(5000)
+	if row == nil || bound[row] != 5000 {
+		t.Fatal("expected row 5000 to be visible and bound to its item")
+	}
+	if top := row.Bounds().Scale(root.Bounds().Size).Y; top < -5.5 || top > -4.5 {
+		t.Fatalf("expected row 5000 half a row above the top, got %g", top)
+	}
+}
+
+// observedItem is a list item that tells bindings when it changes, and counts them
+type observedItem struct {
+	components.Observable
+	Name       string
+	subscribed int
+}
+
+func (o *observedItem) Subscribe(fn func(string)) func() {
+	o.subscribed++
+	unsubscribe := o.Observable.Subscribe(fn)
+	return func() {
+		o.subscribed--
+		unsubscribe()
+	}
+}
+
+// observedSource is a ListSource of observed items
+type observedSource []*observedItem
+
+func (o observedSource) Count() int                   { return len(o) }
+func (o observedSource) ItemAt(index int) interface{} { return o[index] }
+
+func TestListViewUnbindsRows(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{200, 100})
+
+	source := make(observedSource, 100)
+	for i := range source {
+		source[i] = &observedItem{Name: fmt.Sprintf("item %d", i)}
+	}
+	list := MakeListView()
+	list.ItemHeight = 10
+	list.SetTemplate(func() (components.Component, error) {
+		row := &Label{}
+		return row, components.Bind(row, &components.Binding{Attribute: "Text", Path: "Name"})
+	})
+	list.SetSource(source)
+	root.AppendChild(list)
+	root.Layout()
+	if source[0].subscribed != 1 {
+		t.Fatal("expected the first item to be followed by its row")
+	}
+
+	// Rows left without an item stop following their old one
+	list.SetSource(source[:3])
+	root.Layout()
+	if source[5].subscribed != 0 {
+		t.Fatal("expected recycled rows to stop following their items")
+	}
+	if source[2].subscribed != 1 {
+		t.Fatal("expected visible rows to keep following their items")
+	}
+}
+
+// destroyedRow is a row that counts how many times it was destroyed
+type destroyedRow struct {
+	components.Base
+	destroyed *int
+}
+
+func (d *destroyedRow) Destroy() {
+	*d.destroyed++
+}
+
+func TestListViewDestroysRows(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{200, 100})
+
+	destroyed := 0
+	list := MakeListView()
+	list.ItemHeight = 10
+	list.SetTemplate(func() (components.Component, error) {
+		return &destroyedRow{destroyed: &destroyed}, nil
+	})
+	list.SetSource(countSource(100))
+	root.AppendChild(list)
+	root.Layout()
+
+	// Rows made with the old template are thrown away
+	list.SetTemplate(func() (components.Component, error) {
+		return &components.Base{}, nil
+	})
+	if destroyed != 10 {
+		t.Fatalf("expected the 10 old rows to be destroyed, %d were", destroyed)
+	}
+
+	// Destroying the tree gets to the rows kept for later too
+	list.SetTemplate(func() (components.Component, error) {
+		return &destroyedRow{destroyed: &destroyed}, nil
+	})
+	root.Layout()
+	list.SetSource(countSource(3))
+	root.Layout()
+	destroyed = 0
+	components.Destroy(root)
+	if destroyed != 10 {
+		t.Fatalf("expected visible and recycled rows to be destroyed, %d were", destroyed)
+	}
+}
+
+func TestListViewSelection(t *testing.T) {
+	list := MakeListView()
+	list.ItemHeight = 10
+	list.Selection = SelectMultiple
+	list.SetSource(StringList{"a", "b", "c", "d", "e"})
+
+	changes := 0
+	list.OnSelectionChange = func() { changes++ }
+
+	list.Select(1)
+	list.pick(3, input.ModShift)
+	if got := fmt.Sprint(list.SelectedIndices()); got != "[1 2 3]" {
+		t.Fatalf("expected range selection, got %s", got)
+	}
+	list.pick(2, input.ModControl)
+	if got := fmt.Sprint(list.SelectedIndices()); got != "[1 3]" {
+		t.Fatalf("expected control to toggle, got %s", got)
+	}
+
+	// Items that go away are deselected
+	list.SetFocused(true)
+	list.HandleEvent(&components.Event{Type: components.EventKeyDown, Key: input.KeyEnd, Phase: components.PhaseTarget})
+	list.source = StringList{"a", "b"}
+	list.Refresh()
+	if list.SelectedIndex() != -1 || changes != 5 {
+		t.Fatalf("expected empty selection after 5 changes, got %d after %d", list.SelectedIndex(), changes)
+	}
+}
+
+func TestListViewFocusRing(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{100, 100})
+	list := MakeListView()
+	list.ItemHeight = 10
+	list.Selection = SelectMultiple
+	list.SetTemplate(func() (components.Component, error) {
+		return &components.Base{}, nil
+	})
+	list.SetSource(countSource(20))
+	root.AppendChild(list)
+	root.Layout()
+
+	// Control moves the current item without selecting it, it must still be visible
+	list.SetFocused(true)
+	list.SetFocusRingColor(color.RGBA{0xff, 0, 0, 0xff})
+	for i := 0; i < 2; i++ {
+		list.HandleEvent(&components.Event{Type: components.EventKeyDown, Key: input.KeyDown, Modifiers: input.ModControl, Phase: components.PhaseTarget})
+	}
+	if list.SelectedIndex() != -1 {
+		t.Fatalf("expected nothing to be selected, got %d", list.SelectedIndex())
+	}
+
+	renderer := software.MakeRenderer(100, 100, color.Black)
+	root.Draw(renderer)
+	img := renderer.Image()
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	for _, point := range []image.Point{{0, 50}, {50, 0}, {50, 10}, {50, 19}} {
+		if img.RGBAAt(point.X, point.Y) != red {
+			t.Errorf("expected the focus outline at %v, got %v", point, img.RGBAAt(point.X, point.Y))
+		}
+	}
+	if img.RGBAAt(50, 5) == red {
+		t.Error("expected only the current item to be outlined")
+	}
+
+	// No outline without focus
+	list.SetFocused(false)
+	renderer = software.MakeRenderer(100, 100, color.Black)
+	root.Draw(renderer)
+	if renderer.Image().RGBAAt(50, 10) == red {
+		t.Error("expected no outline without focus")
+	}
+}
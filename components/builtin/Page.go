@@ -5,6 +5,7 @@ import (
 	"image"
 
 	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/render"
 )
 
 // Page is a special canvas container with less checks
@@ -28,21 +29,18 @@ func (r *Page) ShouldDraw() bool {
 	return r.Base.ShouldDraw()
 }
 
-func (r *Page) Draw() {
-	r.resizeChildren()
-	r.Base.Draw()
-}
-
-func (r *Page) resizeChildren() {
-	relbounds := components.Bounds{
-		components.Position{0, 0},
-		components.Size{1, 1},
+func (r *Page) Draw(renderer render.Renderer) {
+	if r.Parent() == nil {
+		r.Layout()
 	}
+	r.Base.Draw(renderer)
+}
 
-	// Apply to each children
-	for _, child := range r.Children() {
-		child.SetBounds(relbounds)
-	}
+// Layout measures and arranges the whole tree inside the page size
+func (r *Page) Layout() {
+	bounds := components.BoundsFromRect(r.pixelBounds)
+	r.Measure(bounds.Size)
+	r.Arrange(bounds)
 }
 
 func (r *Page) String() string {
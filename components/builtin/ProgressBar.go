@@ -0,0 +1,125 @@
+package builtin
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// progressBarHeight is the default height of progress bars, in pixels
+const progressBarHeight = 8
+
+// ProgressBar shows how far along something is, as a bar that fills from left to right
+type ProgressBar struct {
+	components.Base
+
+	// TrackColor is the color of the empty part, AccentColor the one of the filled part
+	TrackColor, AccentColor color.Color
+
+	min, max, value float64
+}
+
+// MakeProgressBar creates an empty progress bar going from min to max
+func MakeProgressBar(min, max float64) *ProgressBar {
+	return &ProgressBar{
+		TrackColor:  DefaultControlColors.Normal,
+		AccentColor: DefaultAccentColor,
+		min:         min,
+		max:         max,
+		value:       min,
+	}
+}
+
+// SetRange sets the values for an empty and a full bar
+func (p *ProgressBar) SetRange(min, max float64) {
+	p.min, p.max = min, max
+	p.SetValue(p.value)
+}
+
+// Range returns the values for an empty and a full bar
+func (p *ProgressBar) Range() (min, max float64) {
+	return p.min, p.max
+}
+
+// SetValue sets the progress, clamped to the range
+func (p *ProgressBar) SetValue(value float64) {
+	if value > p.max {
+		value = p.max
+	}
+	if value < p.min {
+		value = p.min
+	}
+	p.value = value
+	p.SetRedraw()
+}
+
+// Value returns the progress
+func (p *ProgressBar) Value() float64 {
+	return p.value
+}
+
+// Fraction returns how full the bar is, from 0 to 1
+func (p *ProgressBar) Fraction() float32 {
+	if p.max <= p.min {
+		return 0
+	}
+	return float32((p.value - p.min) / (p.max - p.min))
+}
+
+// Measure returns a minimum width and the bar height
+func (p *ProgressBar) Measure(available components.Size) components.Size {
+	return p.Constrain(components.Size{Width: sliderMinWidth, Height: progressBarHeight})
+}
+
+// Draw draws the track and the filled part of it
+func (p *ProgressBar) Draw(r render.Renderer) {
+	rect := p.Bounds().Rect()
+	r.FillRect(rect, p.TrackColor)
+	rect.Width *= p.Fraction()
+	r.FillRect(rect, p.AccentColor)
+	p.Base.Draw(r)
+}
+
+// SetAttribute changes Value, Min or Max, for bindings
+func (p *ProgressBar) SetAttribute(name string, value components.Attribute) error {
+	number, err := parseNumber(name, value)
+	if err != nil {
+		return err
+	}
+	switch name {
+	case "Value":
+		p.SetValue(number)
+	case "Min":
+		p.SetRange(number, p.max)
+	case "Max":
+		p.SetRange(p.min, number)
+	default:
+		return errAttributeNotSettable(name)
+	}
+	return nil
+}
+
+func (p *ProgressBar) String() string {
+	return fmt.Sprintf(`<ProgressBar Min="%g" Max="%g" Value="%g" TrackColor="%s" AccentColor="%s" />`,
+		p.min, p.max, p.value, utils.ToHexColor(p.TrackColor), utils.ToHexColor(p.AccentColor))
+}
+
+func makeProgressBar(list components.AttributeList) (components.Component, error) {
+	min, max, value, err := parseRange(list)
+	if err != nil {
+		return nil, err
+	}
+
+	bar := MakeProgressBar(min, max)
+	bar.SetValue(value)
+	if bar.TrackColor, err = parseColor(list, "TrackColor", bar.TrackColor); err != nil {
+		return nil, err
+	}
+	if bar.AccentColor, err = parseColor(list, "AccentColor", bar.AccentColor); err != nil {
+		return nil, err
+	}
+	return bar, nil
+}
@@ -0,0 +1,83 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/utils"
+)
+
+// RadioButton is a check box that, when checked, unchecks all the other radio buttons in the
+// same group. Groups are by name and span the whole tree radio buttons are in.
+type RadioButton struct {
+	CheckBox
+
+	// Group is the name of the group the radio button is in
+	Group string
+}
+
+// MakeRadioButton creates an unchecked radio button with a text label
+func MakeRadioButton(label, group string) *RadioButton {
+	radio := &RadioButton{Group: group}
+	radio.init(label)
+	radio.markInset = 0.3
+	radio.activate = func() {
+		if !radio.checked {
+			radio.Check()
+		}
+	}
+	return radio
+}
+
+// Check checks the radio button and unchecks the others in its group, calling OnChange on
+// all the ones that changed
+func (r *RadioButton) Check() {
+	for _, other := range r.GroupButtons() {
+		if other != r && other.checked {
+			other.SetChecked(false)
+			other.changed()
+		}
+	}
+	r.SetChecked(true)
+	r.changed()
+}
+
+// GroupButtons returns all the radio buttons in the same group (including this one)
+func (r *RadioButton) GroupButtons() []*RadioButton {
+	buttons := []*RadioButton{r}
+	var walk func(components.Component)
+	walk = func(parent components.Component) {
+		for _, child := range parent.Children() {
+			if radio, ok := child.(*RadioButton); ok && radio != r && radio.Group == r.Group {
+				buttons = append(buttons, radio)
+			}
+			walk(child)
+		}
+	}
+	walk(r.Root())
+	return buttons
+}
+
+// Selected returns the checked radio button in the group, if any
+func (r *RadioButton) Selected() *RadioButton {
+	for _, radio := range r.GroupButtons() {
+		if radio.checked {
+			return radio
+		}
+	}
+	return nil
+}
+
+func (r *RadioButton) String() string {
+	return fmt.Sprintf(`<RadioButton Text="%s" Group="%s" FontFace="%s" FontSize="%g" Color="%s" Checked="%t" Disabled="%t" %s />`,
+		escapeAttribute(r.Content()), escapeAttribute(r.Group), escapeAttribute(r.FontFace()), r.FontSize(),
+		utils.ToHexColor(r.Color()), r.checked, r.Disabled(), formatStateColors(r.Colors))
+}
+
+func makeRadioButton(list components.AttributeList) (components.Component, error) {
+	radio := MakeRadioButton("", list.Get("Group", "").String())
+	if err := parseCheckAttributes(&radio.CheckBox, list); err != nil {
+		return nil, err
+	}
+	return radio, nil
+}
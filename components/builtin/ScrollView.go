@@ -0,0 +1,413 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// ScrollView settings, in pixels
+const (
+	// scrollBarSize is how thick scroll bars are
+	scrollBarSize = 8
+	// minThumbSize is how short scroll bar thumbs can get for very long content
+	minThumbSize = 20
+	// DefaultScrollStep is how much one step of the mouse wheel scrolls
+	DefaultScrollStep = 40
+)
+
+// Default ScrollView colors
+var (
+	DefaultScrollBarColor   = color.RGBA{0x20, 0x20, 0x20, 0x80}
+	DefaultScrollThumbColor = color.RGBA{0xa0, 0xa0, 0xa0, 0xc0}
+)
+
+// ScrollView is a container that shows a part of its content, which can be moved around with
+// the mouse wheel, by dragging it or with the scroll bars. Children are laid out on top of
+// each other, like in a Canvas, in a space as big as they want to be.
+type ScrollView struct {
+	components.Base
+
+	// Horizontal and Vertical are whether the content can be scrolled on each axis, content
+	// is as wide (or tall) as the view on axes that don't scroll
+	Horizontal, Vertical bool
+	// DragToScroll is whether the content can be scrolled by dragging it with the mouse
+	DragToScroll bool
+	// ScrollStep is how many pixels a step of the mouse wheel scrolls
+	ScrollStep float32
+	// BarColor and ThumbColor are the colors of the scroll bars
+	BarColor, ThumbColor color.Color
+	// OnScroll is called when the offset changes
+	OnScroll func(x, y float32)
+
+	offset   components.Position
+	content  components.Size
+	viewport components.Size
+
+	// dragging is set while the content or a thumb (dragThumb) is dragged, dragStart is
+	// where the cursor was (in pixels) and dragOffset the offset at that time
+	dragging   bool
+	dragThumb  bool
+	dragAxis   int
+	dragStart  components.Position
+	dragOffset components.Position
+}
+
+// MakeScrollView creates a vertically scrolling view
+func MakeScrollView() *ScrollView {
+	view := &ScrollView{}
+	view.init()
+	return view
+}
+
+// init sets the defaults and binds the listeners, it's separate from MakeScrollView so that
+// components embedding a ScrollView can call it on their own copy
+func (s *ScrollView) init() {
+	s.Vertical = true
+	s.DragToScroll = true
+	s.ScrollStep = DefaultScrollStep
+	s.BarColor = DefaultScrollBarColor
+	s.ThumbColor = DefaultScrollThumbColor
+
+	// Content outside of the view is hidden
+	s.SetClipChildren(true)
+	s.AddEventListener(components.EventScroll, s.onWheel)
+	s.AddCaptureListener(components.EventMouseDown, s.onBarMouseDown)
+	s.AddEventListener(components.EventMouseDown, s.onMouseDown)
+	s.AddEventListener(components.EventMouseMove, s.onMouseMove)
+	s.AddEventListener(components.EventMouseUp, func(ev *components.Event) {
+		s.dragging = false
+	})
+}
+
+// ScrollOffset returns how far (in pixels) the content is scrolled from its top-left corner
+func (s *ScrollView) ScrollOffset() (x, y float32) {
+	return s.offset.X, s.offset.Y
+}
+
+// SetScrollOffset scrolls the content to an offset, clamped so that content never leaves
+// empty space in the view
+func (s *ScrollView) SetScrollOffset(x, y float32) {
+	maxX, maxY := s.MaxScrollOffset()
+	x, y = clamp(x, 0, maxX), clamp(y, 0, maxY)
+	if !s.Horizontal {
+		x = 0
+	}
+	if !s.Vertical {
+		y = 0
+	}
+	if x == s.offset.X && y == s.offset.Y {
+		return
+	}
+	s.offset = components.Position{X: x, Y: y}
+	s.SetRedraw()
+	if s.OnScroll != nil {
+		s.OnScroll(x, y)
+	}
+}
+
+// ScrollBy moves the content by some pixels
+func (s *ScrollView) ScrollBy(dx, dy float32) {
+	s.SetScrollOffset(s.offset.X+dx, s.offset.Y+dy)
+}
+
+// State returns the scroll offset, see components.StateHolder
+func (s *ScrollView) State() interface{} {
+	return s.offset
+}
+
+// SetState restores a scroll offset, which is clamped on the next layout
+func (s *ScrollView) SetState(state interface{}) {
+	if offset, ok := state.(components.Position); ok {
+		s.offset = offset
+		s.SetRedraw()
+	}
+}
+
+// ScrollIntoView scrolls as little as possible so that a rectangle of the content (in
+// pixels, relative to the content's top-left corner) is visible
+func (s *ScrollView) ScrollIntoView(rect components.Bounds) {
+	x, y := s.offset.X, s.offset.Y
+	if rect.X+rect.Width > x+s.viewport.Width {
+		x = rect.X + rect.Width - s.viewport.Width
+	}
+	if rect.X < x {
+		x = rect.X
+	}
+	if rect.Y+rect.Height > y+s.viewport.Height {
+		y = rect.Y + rect.Height - s.viewport.Height
+	}
+	if rect.Y < y {
+		y = rect.Y
+	}
+	s.SetScrollOffset(x, y)
+}
+
+// ContentSize returns the size of the content, as of the last layout
+func (s *ScrollView) ContentSize() components.Size {
+	return s.content
+}
+
+// MaxScrollOffset returns how far the content can be scrolled on each axis
+func (s *ScrollView) MaxScrollOffset() (x, y float32) {
+	x, y = s.content.Width-s.viewport.Width, s.content.Height-s.viewport.Height
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return
+}
+
+// Measure measures children with no limit on the scrolling axes, and asks for as much
+// space as they need (up to what's available)
+func (s *ScrollView) Measure(available components.Size) components.Size {
+	inner := available
+	if s.Horizontal {
+		inner.Width = components.Unbounded
+	}
+	if s.Vertical {
+		inner.Height = components.Unbounded
+	}
+	s.content = s.Base.Measure(inner)
+
+	size := s.content
+	if size.Width > available.Width {
+		size.Width = available.Width
+	}
+	if size.Height > available.Height {
+		size.Height = available.Height
+	}
+	return s.Constrain(size)
+}
+
+// Arrange places the content at the scroll offset, at least as big as the view
+func (s *ScrollView) Arrange(pixels components.Bounds) {
+	s.SetPixelBounds(pixels)
+	s.viewport = pixels.Size
+
+	// Content fills the view on axes that don't scroll
+	if !s.Horizontal || s.content.Width < s.viewport.Width {
+		s.content.Width = s.viewport.Width
+	}
+	if !s.Vertical || s.content.Height < s.viewport.Height {
+		s.content.Height = s.viewport.Height
+	}
+	s.SetScrollOffset(s.offset.X, s.offset.Y)
+
+	content := components.Bounds{
+		Position: components.Position{X: pixels.X - s.offset.X, Y: pixels.Y - s.offset.Y},
+		Size:     s.content,
+	}
+	for _, child := range s.Children() {
+		child.Arrange(content)
+	}
+}
+
+// bars returns where the vertical and horizontal scroll bars are (in pixels), and whether
+// they are shown at all
+func (s *ScrollView) bars() (bars [2]components.Bounds, shown [2]bool) {
+	bounds := s.PixelBounds()
+	maxX, maxY := s.MaxScrollOffset()
+	shown[0] = s.Vertical && maxY > 0
+	shown[1] = s.Horizontal && maxX > 0
+	bars[0] = components.Bounds{
+		Position: components.Position{X: bounds.X + bounds.Width - scrollBarSize, Y: bounds.Y},
+		Size:     components.Size{Width: scrollBarSize, Height: bounds.Height},
+	}
+	bars[1] = components.Bounds{
+		Position: components.Position{X: bounds.X, Y: bounds.Y + bounds.Height - scrollBarSize},
+		Size:     components.Size{Width: bounds.Width, Height: scrollBarSize},
+	}
+	// Leave the corner to the vertical bar
+	if shown[0] && shown[1] {
+		bars[1].Width -= scrollBarSize
+	}
+	return
+}
+
+// thumb returns where the thumb of a scroll bar is (0 is vertical, 1 horizontal)
+func (s *ScrollView) thumb(bar components.Bounds, axis int) components.Bounds {
+	length, view, content, offset := bar.Height, s.viewport.Height, s.content.Height, s.offset.Y
+	if axis == 1 {
+		length, view, content, offset = bar.Width, s.viewport.Width, s.content.Width, s.offset.X
+	}
+	size := length * view / content
+	if size < minThumbSize {
+		size = minThumbSize
+	}
+	pos := float32(0)
+	if content > view {
+		pos = (length - size) * offset / (content - view)
+	}
+
+	thumb := bar
+	if axis == 1 {
+		thumb.X, thumb.Width = bar.X+pos, size
+	} else {
+		thumb.Y, thumb.Height = bar.Y+pos, size
+	}
+	return thumb
+}
+
+// toPixels converts a root-relative position to pixels
+func (s *ScrollView) toPixels(pos components.Position) components.Position {
+	return pos.Scale(s.Root().Bounds().Size)
+}
+
+func (s *ScrollView) onWheel(ev *components.Event) {
+	dx, dy := -ev.ScrollX*s.ScrollStep, -ev.ScrollY*s.ScrollStep
+	if !s.Vertical && dx == 0 {
+		// Wheels only scroll vertically, use that for horizontal views
+		dx, dy = dy, 0
+	}
+	oldX, oldY := s.offset.X, s.offset.Y
+	s.ScrollBy(dx, dy)
+	if s.offset.X != oldX || s.offset.Y != oldY {
+		// Nested scroll views only scroll when the innermost one can't
+		ev.StopPropagation()
+	}
+}
+
+func (s *ScrollView) onBarMouseDown(ev *components.Event) {
+	if ev.Button != input.MouseButtonLeft {
+		return
+	}
+	pos := s.toPixels(ev.Position)
+	bars, shown := s.bars()
+	for axis := range bars {
+		if !shown[axis] || !bars[axis].Contains(pos) {
+			continue
+		}
+
+		// Clicking outside the thumb jumps there, then the thumb can be dragged
+		thumb := s.thumb(bars[axis], axis)
+		// (unless the bar is no longer than the thumb)
+		if !thumb.Contains(pos) {
+			maxX, maxY := s.MaxScrollOffset()
+			if axis == 1 {
+				if free := bars[axis].Width - thumb.Width; free > 0 {
+					s.SetScrollOffset((pos.X-bars[axis].X-thumb.Width/2)/free*maxX, s.offset.Y)
+				}
+			} else if free := bars[axis].Height - thumb.Height; free > 0 {
+				s.SetScrollOffset(s.offset.X, (pos.Y-bars[axis].Y-thumb.Height/2)/free*maxY)
+			}
+		}
+		s.startDrag(pos, true, axis)
+		ev.StopPropagation()
+		return
+	}
+}
+
+func (s *ScrollView) onMouseDown(ev *components.Event) {
+	// Don't steal drags from controls that use them
+	if !s.DragToScroll || ev.Button != input.MouseButtonLeft || components.CanFocus(ev.Target) {
+		return
+	}
+	s.startDrag(s.toPixels(ev.Position), false, 0)
+}
+
+func (s *ScrollView) startDrag(pos components.Position, thumb bool, axis int) {
+	s.dragging, s.dragThumb, s.dragAxis = true, thumb, axis
+	s.dragStart = pos
+	s.dragOffset = s.offset
+}
+
+func (s *ScrollView) onMouseMove(ev *components.Event) {
+	if !s.dragging {
+		return
+	}
+	pos := s.toPixels(ev.Position)
+	dx, dy := pos.X-s.dragStart.X, pos.Y-s.dragStart.Y
+	if !s.dragThumb {
+		// Content follows the cursor
+		s.SetScrollOffset(s.dragOffset.X-dx, s.dragOffset.Y-dy)
+		return
+	}
+
+	// Thumbs move as much as the cursor, content moves proportionally
+	bars, _ := s.bars()
+	bar := bars[s.dragAxis]
+	thumb := s.thumb(bar, s.dragAxis)
+	maxX, maxY := s.MaxScrollOffset()
+	if s.dragAxis == 1 {
+		if free := bar.Width - thumb.Width; free > 0 {
+			s.SetScrollOffset(s.dragOffset.X+dx/free*maxX, s.offset.Y)
+		}
+	} else if free := bar.Height - thumb.Height; free > 0 {
+		s.SetScrollOffset(s.offset.X, s.dragOffset.Y+dy/free*maxY)
+	}
+}
+
+// Draw draws the visible part of the content and the scroll bars
+func (s *ScrollView) Draw(r render.Renderer) {
+	s.Base.Draw(r)
+
+	// Bars go over the content
+	root := s.Root().Bounds().Size.Inverse()
+	bars, shown := s.bars()
+	for axis, bar := range bars {
+		if !shown[axis] {
+			continue
+		}
+		r.FillRect(bar.Scale(root).Rect(), s.BarColor)
+		r.FillRect(s.thumb(bar, axis).Scale(root).Rect(), s.ThumbColor)
+	}
+}
+
+func (s *ScrollView) String() string {
+	return fmt.Sprintf("<ScrollView Horizontal=\"%t\" Vertical=\"%t\" DragToScroll=\"%t\" ScrollStep=\"%g\" BarColor=\"%s\" ThumbColor=\"%s\">\n%s</ScrollView>",
+		s.Horizontal, s.Vertical, s.DragToScroll, s.ScrollStep, utils.ToHexColor(s.BarColor), utils.ToHexColor(s.ThumbColor), s.ChildrenStr())
+}
+
+func clamp(value, min, max float32) float32 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func makeScrollView(list components.AttributeList) (components.Component, error) {
+	view := MakeScrollView()
+
+	flags := []struct {
+		name  string
+		value *bool
+	}{
+		{"Horizontal", &view.Horizontal},
+		{"Vertical", &view.Vertical},
+		{"DragToScroll", &view.DragToScroll},
+	}
+	for _, flag := range flags {
+		if attr, ok := list[flag.name]; ok {
+			value, err := attr.Bool()
+			if err != nil {
+				return nil, fmt.Errorf("%s must be either true or false", flag.name)
+			}
+			*flag.value = value
+		}
+	}
+
+	step, err := list.Get("ScrollStep", fmt.Sprint(DefaultScrollStep)).Float32()
+	if err != nil || step <= 0 {
+		return nil, errors.New("ScrollStep must be a positive number")
+	}
+	view.ScrollStep = step
+
+	if view.BarColor, err = parseColor(list, "BarColor", view.BarColor); err != nil {
+		return nil, err
+	}
+	if view.ThumbColor, err = parseColor(list, "ThumbColor", view.ThumbColor); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
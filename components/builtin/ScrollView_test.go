@@ -0,0 +1,39 @@
+package builtin
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hamcha/youi/components"
+)
+
+func TestScrollViewOffset(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{200, 100})
+
+	view := MakeScrollView()
+	content := &components.Base{}
+	content.SetConstraints(components.Size{Width: 0, Height: 300}, components.Size{Width: components.Unbounded, Height: components.Unbounded})
+	view.AppendChild(content)
+	root.AppendChild(view)
+	root.Layout()
+
+	if _, maxY := view.MaxScrollOffset(); maxY != 200 {
+		t.Fatalf("expected to scroll up to 200px, got %g", maxY)
+	}
+
+	view.ScrollBy(50, 500)
+	root.Layout()
+	if x, y := view.ScrollOffset(); x != 0 || y != 200 {
+		t.Fatalf("expected offset to be clamped to (0, 200), got (%g, %g)", x, y)
+	}
+	if top := content.Bounds().Scale(root.Bounds().Size).Y; top < -200.5 || top > -199.5 {
+		t.Fatalf("expected content to start at -200px, got %g", top)
+	}
+
+	// Content scrolled out of the view can't be clicked
+	outside := components.Position{X: 0.5, Y: 1.5}
+	if hit := components.HitTest(root, outside); hit == content {
+		t.Fatal("hit content outside of the view")
+	}
+}
@@ -0,0 +1,320 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// Slider sizes, in pixels
+const (
+	sliderThumbSize   = 14
+	sliderTrackHeight = 4
+	sliderMinWidth    = 100
+)
+
+// Slider is a control for picking a number in a range by dragging a thumb along a track
+type Slider struct {
+	components.Base
+	components.ControlState
+
+	// Colors are the thumb colors for each visual state
+	Colors components.StateColors
+	// TrackColor is the color of the track, AccentColor the one of the part before the thumb
+	TrackColor, AccentColor color.Color
+	// OnChange is called when the value is changed by the user
+	OnChange func(value float64)
+
+	min, max, value, step float64
+	dragging              bool
+}
+
+// MakeSlider creates a slider going from min to max, step is the smallest change (0 means
+// any value can be picked)
+func MakeSlider(min, max, step float64) *Slider {
+	slider := &Slider{
+		Colors:      DefaultControlColors,
+		TrackColor:  DefaultControlColors.Normal,
+		AccentColor: DefaultAccentColor,
+		min:         min,
+		max:         max,
+		value:       min,
+		step:        step,
+	}
+	slider.Colors.Normal = color.RGBA{0xc0, 0xc0, 0xc0, 0xff}
+	slider.Colors.Hovered = color.White
+	slider.BindEvents(slider)
+	slider.AddEventListener(components.EventMouseDown, slider.onMouseDown)
+	slider.AddEventListener(components.EventMouseMove, slider.onMouseMove)
+	slider.AddEventListener(components.EventMouseUp, func(ev *components.Event) {
+		slider.dragging = false
+	})
+	slider.AddEventListener(components.EventKeyDown, slider.onKeyDown)
+	return slider
+}
+
+// SetRange sets the minimum and maximum values and the step between values
+func (s *Slider) SetRange(min, max, step float64) {
+	s.min, s.max, s.step = min, max, step
+	s.SetValue(s.value)
+}
+
+// Range returns the minimum and maximum values and the step between values
+func (s *Slider) Range() (min, max, step float64) {
+	return s.min, s.max, s.step
+}
+
+// SetValue sets the slider value, snapped to the range and step, without calling OnChange
+func (s *Slider) SetValue(value float64) {
+	s.value = s.snap(value)
+	s.SetRedraw()
+}
+
+// Value returns the slider value
+func (s *Slider) Value() float64 {
+	return s.value
+}
+
+// State returns the slider value, see components.StateHolder
+func (s *Slider) State() interface{} {
+	return s.value
+}
+
+// SetState restores the slider value
+func (s *Slider) SetState(state interface{}) {
+	if value, ok := state.(float64); ok {
+		s.SetValue(value)
+	}
+}
+
+// snap clamps a value to the range and rounds it to the closest step
+func (s *Slider) snap(value float64) float64 {
+	if s.step > 0 {
+		value = s.min + math.Round((value-s.min)/s.step)*s.step
+	}
+	if value > s.max {
+		value = s.max
+	}
+	if value < s.min {
+		value = s.min
+	}
+	return value
+}
+
+// change sets the value as the user did and calls OnChange if it changed
+func (s *Slider) change(value float64) {
+	old := s.value
+	s.SetValue(value)
+	if s.value != old {
+		if s.OnChange != nil {
+			s.OnChange(s.value)
+		}
+		notifyChange(s)
+	}
+}
+
+// fraction returns where the value is in the range, from 0 to 1
+func (s *Slider) fraction() float32 {
+	if s.max <= s.min {
+		return 0
+	}
+	return float32((s.value - s.min) / (s.max - s.min))
+}
+
+// valueAt returns the value at an horizontal position (relative to the root)
+func (s *Slider) valueAt(x float32) float64 {
+	bounds := s.PixelBounds()
+	thumb := float32(sliderThumbSize)
+	track := bounds.Width - thumb
+	if track <= 0 {
+		return s.min
+	}
+	pos := (x*s.Root().Bounds().Width - bounds.X - thumb/2) / track
+	return s.min + float64(pos)*(s.max-s.min)
+}
+
+func (s *Slider) onMouseDown(ev *components.Event) {
+	if s.Disabled() || ev.Button != input.MouseButtonLeft {
+		return
+	}
+	s.dragging = true
+	s.change(s.valueAt(ev.Position.X))
+}
+
+func (s *Slider) onMouseMove(ev *components.Event) {
+	if s.dragging && !s.Disabled() {
+		s.change(s.valueAt(ev.Position.X))
+	}
+}
+
+func (s *Slider) onKeyDown(ev *components.Event) {
+	if !s.Focused() {
+		return
+	}
+	step := s.step
+	if step <= 0 {
+		step = (s.max - s.min) / 100
+	}
+	switch ev.Key {
+	case input.KeyLeft, input.KeyDown:
+		s.change(s.value - step)
+	case input.KeyRight, input.KeyUp:
+		s.change(s.value + step)
+	case input.KeyHome:
+		s.change(s.min)
+	case input.KeyEnd:
+		s.change(s.max)
+	default:
+		return
+	}
+	ev.StopPropagation()
+}
+
+// Measure returns a minimum width and the thumb height
+func (s *Slider) Measure(available components.Size) components.Size {
+	return s.Constrain(components.Size{Width: sliderMinWidth, Height: sliderThumbSize})
+}
+
+// ShouldDraw returns whether the slider needs to be re-drawn
+func (s *Slider) ShouldDraw() bool {
+	return s.Base.ShouldDraw() || s.StateChanged()
+}
+
+// Draw draws the track, the filled part of it and the thumb
+func (s *Slider) Draw(r render.Renderer) {
+	bounds := s.Bounds().Rect()
+	surface := r.Size()
+	thumbW, thumbH := sliderThumbSize/float32(surface.X), sliderThumbSize/float32(surface.Y)
+	trackH := sliderTrackHeight / float32(surface.Y)
+
+	// The track goes from the center of the thumb at min to the center of the thumb at max
+	track := render.Rect{
+		X:      bounds.X + thumbW/2,
+		Y:      bounds.Y + (bounds.Height-trackH)/2,
+		Width:  bounds.Width - thumbW,
+		Height: trackH,
+	}
+	r.FillRect(track, s.TrackColor)
+	filled := track
+	filled.Width *= s.fraction()
+	accent := s.AccentColor
+	if s.Disabled() {
+		accent = DefaultDisabledColor
+	}
+	r.FillRect(filled, accent)
+
+	thumb := render.Rect{
+		X:      track.X + filled.Width - thumbW/2,
+		Y:      bounds.Y + (bounds.Height-thumbH)/2,
+		Width:  thumbW,
+		Height: thumbH,
+	}
+	r.FillRect(thumb, s.Colors.Get(s.VisualState()))
+	s.DrawFocusRing(r, thumb)
+
+	s.Base.Draw(r)
+	s.ClearState()
+}
+
+// SetAttribute changes Value, Min, Max, Step or Disabled, for bindings
+func (s *Slider) SetAttribute(name string, value components.Attribute) error {
+	if name == "Disabled" {
+		return setDisabled(&s.ControlState, value)
+	}
+	number, err := parseNumber(name, value)
+	if err != nil {
+		return err
+	}
+	switch name {
+	case "Value":
+		s.SetValue(number)
+	case "Min":
+		s.SetRange(number, s.max, s.step)
+	case "Max":
+		s.SetRange(s.min, number, s.step)
+	case "Step":
+		s.SetRange(s.min, s.max, number)
+	default:
+		return errAttributeNotSettable(name)
+	}
+	return nil
+}
+
+// GetAttribute returns Value, which the user can change
+func (s *Slider) GetAttribute(name string) (components.Attribute, bool) {
+	if name == "Value" {
+		return formatFloat(s.value), true
+	}
+	return "", false
+}
+
+func (s *Slider) String() string {
+	return fmt.Sprintf(`<Slider Min="%g" Max="%g" Step="%g" Value="%g" Disabled="%t" TrackColor="%s" AccentColor="%s" />`,
+		s.min, s.max, s.step, s.value, s.Disabled(), utils.ToHexColor(s.TrackColor), utils.ToHexColor(s.AccentColor))
+}
+
+// parseRange reads the Min, Max and Value attributes shared by sliders and progress bars
+func parseRange(list components.AttributeList) (min, max, value float64, err error) {
+	fmin, err := list.Get("Min", "0").Float32()
+	if err != nil {
+		return 0, 0, 0, errors.New("Min must be a number")
+	}
+	fmax, err := list.Get("Max", "1").Float32()
+	if err != nil || fmax < fmin {
+		return 0, 0, 0, errors.New("Max must be a number greater than Min")
+	}
+	fvalue, err := list.Get("Value", fmt.Sprint(fmin)).Float32()
+	if err != nil {
+		return 0, 0, 0, errors.New("Value must be a number")
+	}
+	return float64(fmin), float64(fmax), float64(fvalue), nil
+}
+
+// parseNumber parses a numeric attribute
+func parseNumber(name string, value components.Attribute) (float64, error) {
+	number, err := strconv.ParseFloat(value.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return number, nil
+}
+
+// parseColor reads an optional color attribute
+func parseColor(list components.AttributeList, name string, def color.Color) (color.Color, error) {
+	attr, ok := list[name]
+	if !ok {
+		return def, nil
+	}
+	return attr.Color()
+}
+
+func makeSlider(list components.AttributeList) (components.Component, error) {
+	min, max, value, err := parseRange(list)
+	if err != nil {
+		return nil, err
+	}
+	step, err := list.Get("Step", "0").Float32()
+	if err != nil || step < 0 {
+		return nil, errors.New("Step must be a positive number")
+	}
+
+	slider := MakeSlider(min, max, float64(step))
+	slider.SetValue(value)
+	if err := parseControlAttributes(&slider.ControlState, list); err != nil {
+		return nil, err
+	}
+	if slider.TrackColor, err = parseColor(list, "TrackColor", slider.TrackColor); err != nil {
+		return nil, err
+	}
+	if slider.AccentColor, err = parseColor(list, "AccentColor", slider.AccentColor); err != nil {
+		return nil, err
+	}
+	return slider, nil
+}
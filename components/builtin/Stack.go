@@ -0,0 +1,475 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hamcha/youi/components"
+)
+
+// Direction is the axis a Stack puts its children on
+type Direction int
+
+// Stack directions
+const (
+	DirectionColumn Direction = iota
+	DirectionRow
+)
+
+var directionNames = map[Direction]string{
+	DirectionColumn: "Column",
+	DirectionRow:    "Row",
+}
+
+func (d Direction) String() string {
+	return directionNames[d]
+}
+
+// Alignment is how children are placed on a Stack's cross axis
+type Alignment int
+
+// Cross axis alignments
+const (
+	AlignStretch Alignment = iota
+	AlignStart
+	AlignCenter
+	AlignEnd
+)
+
+var alignmentNames = map[Alignment]string{
+	AlignStretch: "Stretch",
+	AlignStart:   "Start",
+	AlignCenter:  "Center",
+	AlignEnd:     "End",
+}
+
+func (a Alignment) String() string {
+	return alignmentNames[a]
+}
+
+// Justification is how free space is distributed on a Stack's main axis
+type Justification int
+
+// Main axis justifications
+const (
+	JustifyStart Justification = iota
+	JustifyCenter
+	JustifyEnd
+	JustifySpaceBetween
+	JustifySpaceAround
+	JustifySpaceEvenly
+)
+
+var justificationNames = map[Justification]string{
+	JustifyStart:        "Start",
+	JustifyCenter:       "Center",
+	JustifyEnd:          "End",
+	JustifySpaceBetween: "SpaceBetween",
+	JustifySpaceAround:  "SpaceAround",
+	JustifySpaceEvenly:  "SpaceEvenly",
+}
+
+func (j Justification) String() string {
+	return justificationNames[j]
+}
+
+// Auto means the size is not set and should be worked out by the container
+const Auto = -1
+
+// StackItem holds the layout settings of a single Stack child
+type StackItem struct {
+	// Grow is how much of the free space the child takes, relative to its siblings
+	Grow float32
+	// Shrink is how much the child gives up when there is not enough space
+	Shrink float32
+	// Basis is the starting main axis size in pixels, before growing/shrinking
+	Basis float32
+	// Width and Height are fixed sizes in pixels
+	Width, Height float32
+	// Align overrides the Stack alignment for this child
+	Align    Alignment
+	hasAlign bool
+}
+
+// DefaultStackItem returns the settings children have unless specified otherwise
+func DefaultStackItem() StackItem {
+	return StackItem{
+		Grow:   0,
+		Shrink: 1,
+		Basis:  Auto,
+		Width:  Auto,
+		Height: Auto,
+	}
+}
+
+// SetAlign overrides the Stack alignment for this child
+func (s *StackItem) SetAlign(align Alignment) {
+	s.Align = align
+	s.hasAlign = true
+}
+
+// Stack is a container that lays out its children one after another on a row or column,
+// distributing the available space according to each child's grow and shrink weights
+type Stack struct {
+	components.Base
+
+	Direction Direction
+	Gap       float32
+	Padding   components.Insets
+	Align     Alignment
+	Justify   Justification
+
+	items map[components.Component]*StackItem
+}
+
+// Item returns the layout settings of a child, so they can be changed
+func (s *Stack) Item(child components.Component) *StackItem {
+	if s.items == nil {
+		s.items = make(map[components.Component]*StackItem)
+	}
+	item, ok := s.items[child]
+	if !ok {
+		def := DefaultStackItem()
+		item = &def
+		s.items[child] = item
+	}
+	return item
+}
+
+// RemoveChild removes a child and forgets its layout settings
+func (s *Stack) RemoveChild(child components.Component) error {
+	if err := s.Base.RemoveChild(child); err != nil {
+		return err
+	}
+	delete(s.items, child)
+	return nil
+}
+
+// RemoveChildByIndex removes the ith child and forgets its layout settings
+func (s *Stack) RemoveChildByIndex(i int) error {
+	var child components.Component
+	if children := s.Children(); i >= 0 && i < len(children) {
+		child = children[i]
+	}
+	if err := s.Base.RemoveChildByIndex(i); err != nil {
+		return err
+	}
+	delete(s.items, child)
+	return nil
+}
+
+// SetChildSettings parses per-child settings (Grow, Shrink, Basis, Width, Height, Align)
+func (s *Stack) SetChildSettings(child components.Component, settings components.AttributeList) error {
+	item := s.Item(child)
+	for name, value := range settings {
+		var err error
+		switch name {
+		case "Grow":
+			item.Grow, err = value.Float32()
+		case "Shrink":
+			item.Shrink, err = value.Float32()
+		case "Basis":
+			item.Basis, err = value.Float32()
+		case "Width":
+			item.Width, err = value.Float32()
+		case "Height":
+			item.Height, err = value.Float32()
+		case "Align":
+			var align Alignment
+			align, err = parseAlignment(value.String())
+			item.SetAlign(align)
+		default:
+			return fmt.Errorf("unknown Stack setting \"%s\"", name)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for Stack.%s: %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+// axis helpers, to write the layout once for both directions
+func (s *Stack) main(size components.Size) float32 {
+	if s.Direction == DirectionRow {
+		return size.Width
+	}
+	return size.Height
+}
+
+func (s *Stack) cross(size components.Size) float32 {
+	if s.Direction == DirectionRow {
+		return size.Height
+	}
+	return size.Width
+}
+
+func (s *Stack) makeSize(main, cross float32) components.Size {
+	if s.Direction == DirectionRow {
+		return components.Size{Width: main, Height: cross}
+	}
+	return components.Size{Width: cross, Height: main}
+}
+
+// itemSizes returns the fixed sizes of a child, if any, on both axes
+func (s *Stack) itemSizes(item *StackItem) (main, cross float32) {
+	if s.Direction == DirectionRow {
+		main, cross = item.Width, item.Height
+	} else {
+		main, cross = item.Height, item.Width
+	}
+	if item.Basis != Auto {
+		main = item.Basis
+	}
+	return
+}
+
+// childSize returns the size a child wants, using fixed sizes where set and measuring it otherwise
+func (s *Stack) childSize(child components.Component, available components.Size) (main, cross float32) {
+	main, cross = s.itemSizes(s.Item(child))
+	if main == Auto || cross == Auto {
+		desired := child.Measure(available)
+		if main == Auto {
+			main = s.main(desired)
+		}
+		if cross == Auto {
+			cross = s.cross(desired)
+		}
+	}
+	return
+}
+
+// Measure returns the size of all children put one after another, plus gaps and padding
+func (s *Stack) Measure(available components.Size) components.Size {
+	inner := components.Bounds{Size: available}.Shrink(s.Padding).Size
+
+	var main, cross float32
+	for i, child := range s.Children() {
+		childMain, childCross := s.childSize(child, inner)
+		main += childMain
+		if i > 0 {
+			main += s.Gap
+		}
+		if childCross > cross {
+			cross = childCross
+		}
+	}
+
+	size := s.makeSize(main, cross)
+	size.Width += s.Padding.Left + s.Padding.Right
+	size.Height += s.Padding.Top + s.Padding.Bottom
+	return s.Constrain(size)
+}
+
+// Arrange places the children on the stack axis, growing or shrinking them to fill it
+func (s *Stack) Arrange(pixels components.Bounds) {
+	s.SetPixelBounds(pixels)
+
+	children := s.Children()
+	if len(children) == 0 {
+		return
+	}
+
+	inner := pixels.Shrink(s.Padding)
+	available := s.main(inner.Size) - s.Gap*float32(len(children)-1)
+	crossAvailable := s.cross(inner.Size)
+
+	// Get starting sizes
+	sizes := make([]float32, len(children))
+	crossSizes := make([]float32, len(children))
+	var used, totalGrow, totalShrink float32
+	for i, child := range children {
+		item := s.Item(child)
+		sizes[i], crossSizes[i] = s.childSize(child, inner.Size)
+		used += sizes[i]
+		totalGrow += item.Grow
+		totalShrink += item.Shrink * sizes[i]
+	}
+
+	// Grow or shrink to fill the available space
+	free := available - used
+	if free > 0 && totalGrow > 0 {
+		for i, child := range children {
+			sizes[i] += free * s.Item(child).Grow / totalGrow
+		}
+	} else if free < 0 && totalShrink > 0 {
+		for i, child := range children {
+			sizes[i] += free * s.Item(child).Shrink * sizes[i] / totalShrink
+			if sizes[i] < 0 {
+				sizes[i] = 0
+			}
+		}
+	}
+
+	// Honor min/max sizes, then see how much space is actually left
+	used = 0
+	for i, child := range children {
+		min, max := child.Constraints()
+		size := components.ConstrainSize(s.makeSize(sizes[i], 0), min, max)
+		sizes[i] = s.main(size)
+		used += sizes[i]
+	}
+	free = available - used
+
+	// Distribute what's left according to justification
+	offset, spacing := justify(s.Justify, free, len(children))
+	pos := s.main(components.Size{Width: inner.X, Height: inner.Y}) + offset
+	crossStart := s.cross(components.Size{Width: inner.X, Height: inner.Y})
+
+	for i, child := range children {
+		item := s.Item(child)
+		_, fixedCross := s.itemSizes(item)
+
+		align := s.Align
+		if item.hasAlign {
+			align = item.Align
+		}
+
+		// Stretch children with no fixed cross size
+		crossSize := crossSizes[i]
+		if align == AlignStretch && fixedCross == Auto {
+			crossSize = crossAvailable
+		}
+		min, max := child.Constraints()
+		crossSize = s.cross(components.ConstrainSize(s.makeSize(0, crossSize), min, max))
+
+		crossPos := crossStart
+		switch align {
+		case AlignCenter:
+			crossPos += (crossAvailable - crossSize) / 2
+		case AlignEnd:
+			crossPos += crossAvailable - crossSize
+		}
+
+		var bounds components.Bounds
+		if s.Direction == DirectionRow {
+			bounds = components.Bounds{
+				Position: components.Position{X: pos, Y: crossPos},
+				Size:     components.Size{Width: sizes[i], Height: crossSize},
+			}
+		} else {
+			bounds = components.Bounds{
+				Position: components.Position{X: crossPos, Y: pos},
+				Size:     components.Size{Width: crossSize, Height: sizes[i]},
+			}
+		}
+		child.Arrange(bounds)
+
+		pos += sizes[i] + s.Gap + spacing
+	}
+}
+
+// justify returns where the first child starts and how much extra space goes between children
+func justify(j Justification, free float32, count int) (offset, spacing float32) {
+	if free <= 0 {
+		return 0, 0
+	}
+	switch j {
+	case JustifyCenter:
+		offset = free / 2
+	case JustifyEnd:
+		offset = free
+	case JustifySpaceBetween:
+		if count > 1 {
+			spacing = free / float32(count-1)
+		}
+	case JustifySpaceAround:
+		spacing = free / float32(count)
+		offset = spacing / 2
+	case JustifySpaceEvenly:
+		spacing = free / float32(count+1)
+		offset = spacing
+	}
+	return
+}
+
+func (s *Stack) childrenStr() (out string) {
+	for _, child := range s.Children() {
+		str := fmt.Sprint(child)
+		if item, ok := s.items[child]; ok {
+			str = addSettings(str, "Stack", item.settings())
+		}
+		out += indent(str) + "\n"
+	}
+	return
+}
+
+func (s StackItem) settings() (out []string) {
+	def := DefaultStackItem()
+	if s.Grow != def.Grow {
+		out = append(out, fmt.Sprintf("Grow=\"%g\"", s.Grow))
+	}
+	if s.Shrink != def.Shrink {
+		out = append(out, fmt.Sprintf("Shrink=\"%g\"", s.Shrink))
+	}
+	if s.Basis != def.Basis {
+		out = append(out, fmt.Sprintf("Basis=\"%g\"", s.Basis))
+	}
+	if s.Width != def.Width {
+		out = append(out, fmt.Sprintf("Width=\"%g\"", s.Width))
+	}
+	if s.Height != def.Height {
+		out = append(out, fmt.Sprintf("Height=\"%g\"", s.Height))
+	}
+	if s.hasAlign {
+		out = append(out, fmt.Sprintf("Align=\"%s\"", s.Align))
+	}
+	return
+}
+
+func (s *Stack) String() string {
+	return fmt.Sprintf(`<Stack Direction="%s" Gap="%g" Padding="%s" Align="%s" Justify="%s">
+%s</Stack>`,
+		s.Direction, s.Gap, s.Padding, s.Align, s.Justify, s.childrenStr())
+}
+
+func parseAlignment(str string) (Alignment, error) {
+	for align, name := range alignmentNames {
+		if strings.EqualFold(name, str) {
+			return align, nil
+		}
+	}
+	return AlignStretch, errors.New("alignment must be one of Stretch, Start, Center, End")
+}
+
+func makeStack(list components.AttributeList) (components.Component, error) {
+	stack := &Stack{}
+
+	switch strings.ToLower(list.Get("Direction", "Column").String()) {
+	case "column":
+		stack.Direction = DirectionColumn
+	case "row":
+		stack.Direction = DirectionRow
+	default:
+		return nil, errors.New("Direction must be either Row or Column")
+	}
+
+	var err error
+	stack.Gap, err = list.Get("Gap", "0").Float32()
+	if err != nil {
+		return nil, errors.New("Gap must be a number")
+	}
+
+	stack.Padding, err = list.Get("Padding", "0").Insets()
+	if err != nil {
+		return nil, errors.New("Padding must be one, two or four comma-separated numbers")
+	}
+
+	stack.Align, err = parseAlignment(list.Get("Align", "Stretch").String())
+	if err != nil {
+		return nil, err
+	}
+
+	justifyName := list.Get("Justify", "Start").String()
+	found := false
+	for justify, name := range justificationNames {
+		if strings.EqualFold(name, justifyName) {
+			stack.Justify, found = justify, true
+		}
+	}
+	if !found {
+		return nil, errors.New("Justify must be one of Start, Center, End, SpaceBetween, SpaceAround, SpaceEvenly")
+	}
+
+	return stack, nil
+}
@@ -0,0 +1,118 @@
+package builtin
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hamcha/youi/components"
+)
+
+// pixelBounds rounds a child's bounds to pixels, to avoid float noise in comparisons
+func pixelBounds(c components.Component, root *Page) image.Rectangle {
+	b := c.Bounds().Scale(root.Bounds().Size)
+	return image.Rect(int(b.X+0.5), int(b.Y+0.5), int(b.X+b.Width+0.5), int(b.Y+b.Height+0.5))
+}
+
+func TestStackRowGrow(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{200, 100})
+
+	stack := &Stack{Direction: DirectionRow, Gap: 10, Padding: components.Insets{Left: 5, Top: 5, Right: 5, Bottom: 5}}
+	fixed := &components.Base{}
+	grow1 := &components.Base{}
+	grow2 := &components.Base{}
+	stack.AppendChild(fixed)
+	stack.AppendChild(grow1)
+	stack.AppendChild(grow2)
+	stack.Item(fixed).Width = 30
+	stack.Item(grow1).Grow = 1
+	stack.Item(grow2).Grow = 3
+	root.AppendChild(stack)
+
+	root.Layout()
+
+	// 190 inner width - 20 of gaps - 30 fixed = 140 to split 1:3
+	expected := map[components.Component]image.Rectangle{
+		fixed: image.Rect(5, 5, 35, 95),
+		grow1: image.Rect(45, 5, 80, 95),
+		grow2: image.Rect(90, 5, 195, 95),
+	}
+	for child, rect := range expected {
+		if got := pixelBounds(child, root); got != rect {
+			t.Errorf("expected %v, got %v", rect, got)
+		}
+	}
+}
+
+func TestStackColumnJustifyAlign(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{100, 100})
+
+	stack := &Stack{Direction: DirectionColumn, Justify: JustifyCenter, Align: AlignCenter}
+	child := &components.Base{}
+	stack.AppendChild(child)
+	err := stack.SetChildSettings(child, components.AttributeList{"Width": "20", "Height": "40"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.AppendChild(stack)
+
+	root.Layout()
+
+	if got := pixelBounds(child, root); got != image.Rect(40, 30, 60, 70) {
+		t.Errorf("expected child to be centered, got %v", got)
+	}
+}
+
+func makeSizedImage(width, height int) *Image {
+	img := &Image{}
+	img.SetImage(image.NewRGBA(image.Rect(0, 0, width, height)))
+	return img
+}
+
+func TestStackIntrinsicSizes(t *testing.T) {
+	root := &Page{}
+	root.SetSize(image.Point{200, 100})
+
+	stack := &Stack{Direction: DirectionRow, Align: AlignStart}
+	small := makeSizedImage(30, 20)
+	limited := makeSizedImage(50, 50)
+	limited.SetConstraints(components.Size{}, components.Size{Width: 80})
+	stack.AppendChild(small)
+	stack.AppendChild(limited)
+	stack.Item(limited).Grow = 1
+	root.AppendChild(stack)
+
+	if size := stack.Measure(components.Size{Width: 200, Height: 100}); size != (components.Size{Width: 80, Height: 50}) {
+		t.Errorf("expected stack to measure its content, got %v", size)
+	}
+
+	root.Layout()
+
+	if got := pixelBounds(small, root); got != image.Rect(0, 0, 30, 20) {
+		t.Errorf("expected image to keep its size, got %v", got)
+	}
+	if got := pixelBounds(limited, root); got != image.Rect(30, 0, 110, 50) {
+		t.Errorf("expected growing image to stop at its max width, got %v", got)
+	}
+}
+
+func TestStackRemoveChild(t *testing.T) {
+	stack := &Stack{}
+	first, second := &components.Base{}, &components.Base{}
+	stack.AppendChild(first)
+	stack.AppendChild(second)
+	stack.Item(first).Grow = 1
+	stack.Item(second).Grow = 2
+
+	// Settings of removed children are not kept around
+	if err := stack.RemoveChild(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := stack.RemoveChildByIndex(0); err != nil {
+		t.Fatal(err)
+	}
+	if len(stack.items) != 0 {
+		t.Errorf("expected no settings left, got %d", len(stack.items))
+	}
+}
@@ -0,0 +1,674 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// Default TextBox settings
+var (
+	DefaultTextBoxBackground = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	DefaultPlaceholderColor  = color.RGBA{0x80, 0x80, 0x80, 0xff}
+	DefaultSelectionColor    = color.RGBA{0x33, 0x66, 0xcc, 0xaa}
+	DefaultCaretColor        = color.White
+	DefaultPasswordCharacter = '•'
+)
+
+// maxUndoSteps is how many changes can be undone
+const maxUndoSteps = 100
+
+// TextBox is an editable text field, either single or multi-line
+type TextBox struct {
+	components.Text
+
+	// Background is the color behind the text, nil means no background
+	Background color.Color
+	// SelectionColor is the color drawn behind selected text
+	SelectionColor color.Color
+	// CaretColor is the color of the caret
+	CaretColor color.Color
+	// OnChange is called every time the text is changed by the user
+	OnChange func(text string)
+
+	value       string
+	placeholder string
+	password    bool
+	maxLength   int
+	multiLine   bool
+
+	// caret is where text gets inserted, anchor is the other end of the selection
+	// (both are byte offsets in value)
+	caret, anchor int
+	focused       bool
+	selecting     bool
+
+	undo, redo []textBoxState
+	// typing is set while consecutive characters are being typed, so they can be undone together
+	typing bool
+}
+
+// textBoxState is a snapshot of the text box contents, for undo/redo
+type textBoxState struct {
+	value         string
+	caret, anchor int
+}
+
+// MakeTextBox creates an empty text box and sets up its input handling
+func MakeTextBox() *TextBox {
+	box := &TextBox{
+		Background:     DefaultTextBoxBackground,
+		SelectionColor: DefaultSelectionColor,
+		CaretColor:     DefaultCaretColor,
+	}
+	box.AddEventListener(components.EventMouseDown, box.onMouseDown)
+	box.AddEventListener(components.EventMouseMove, box.onMouseMove)
+	box.AddEventListener(components.EventMouseUp, box.onMouseUp)
+	box.AddEventListener(components.EventKeyDown, box.onKeyDown)
+	box.AddEventListener(components.EventChar, box.onChar)
+	box.updateDisplay()
+	return box
+}
+
+// SetValue replaces the text, moving the caret at its end and clearing undo history
+func (t *TextBox) SetValue(str string) {
+	if !t.multiLine {
+		str = singleLine(str)
+	}
+	t.value = t.limit(str, t.value)
+	t.caret, t.anchor = len(t.value), len(t.value)
+	t.undo, t.redo, t.typing = nil, nil, false
+	t.updateDisplay()
+}
+
+// Value returns the text in the text box
+func (t *TextBox) Value() string {
+	return t.value
+}
+
+// SetPlaceholder sets the text shown when the text box is empty
+func (t *TextBox) SetPlaceholder(str string) {
+	t.placeholder = str
+	t.updateDisplay()
+}
+
+// Placeholder returns the text shown when the text box is empty
+func (t *TextBox) Placeholder() string {
+	return t.placeholder
+}
+
+// SetPassword sets whether characters are hidden behind DefaultPasswordCharacter
+func (t *TextBox) SetPassword(password bool) {
+	t.password = password
+	t.updateDisplay()
+}
+
+// Password returns whether characters are hidden
+func (t *TextBox) Password() bool {
+	return t.password
+}
+
+// SetMaxLength sets how many characters the text can have at most, 0 means no limit.
+// Text already in the box is not truncated.
+func (t *TextBox) SetMaxLength(length int) {
+	t.maxLength = length
+}
+
+// MaxLength returns how many characters the text can have at most (0 is no limit)
+func (t *TextBox) MaxLength() int {
+	return t.maxLength
+}
+
+// SetMultiLine sets whether the text box accepts newlines
+func (t *TextBox) SetMultiLine(multiLine bool) {
+	t.multiLine = multiLine
+	if !multiLine {
+		t.SetValue(t.value)
+	}
+}
+
+// MultiLine returns whether the text box accepts newlines
+func (t *TextBox) MultiLine() bool {
+	return t.multiLine
+}
+
+// SetFocused shows or hides the caret, text boxes only take keyboard input while focused
+func (t *TextBox) SetFocused(focused bool) {
+	t.focused = focused
+	t.selecting = false
+	t.typing = false
+	t.SetRedraw()
+}
+
+// Focused returns whether the text box has keyboard focus
+func (t *TextBox) Focused() bool {
+	return t.focused
+}
+
+// Selection returns the start and end (as byte offsets in Value) of the selected text
+func (t *TextBox) Selection() (start, end int) {
+	if t.caret < t.anchor {
+		return t.caret, t.anchor
+	}
+	return t.anchor, t.caret
+}
+
+// Select selects the text between two byte offsets, leaving the caret at the end
+func (t *TextBox) Select(start, end int) {
+	t.anchor, t.caret = t.clamp(start), t.clamp(end)
+	t.typing = false
+	t.SetRedraw()
+}
+
+// SelectedText returns the selected text
+func (t *TextBox) SelectedText() string {
+	start, end := t.Selection()
+	return t.value[start:end]
+}
+
+// Undo goes back to the text before the last change, if any
+func (t *TextBox) Undo() {
+	if len(t.undo) == 0 {
+		return
+	}
+	t.redo = append(t.redo, t.state())
+	t.restore(t.undo[len(t.undo)-1])
+	t.undo = t.undo[:len(t.undo)-1]
+}
+
+// Redo applies again the last undone change, if any
+func (t *TextBox) Redo() {
+	if len(t.redo) == 0 {
+		return
+	}
+	t.undo = append(t.undo, t.state())
+	t.restore(t.redo[len(t.redo)-1])
+	t.redo = t.redo[:len(t.redo)-1]
+}
+
+// Insert replaces the selection with some text, as if the user typed it
+func (t *TextBox) Insert(str string) {
+	if !t.multiLine {
+		str = singleLine(str)
+	}
+	start, end := t.Selection()
+	str = t.limit(str, t.value[start:end])
+	if str == "" && start == end {
+		return
+	}
+	t.edit(t.value[:start]+str+t.value[end:], start+len(str))
+}
+
+// Copy puts the selected text in the clipboard (nothing is copied from password fields)
+func (t *TextBox) Copy() {
+	if t.password || t.caret == t.anchor {
+		return
+	}
+	components.GetClipboard().SetClipboardText(t.SelectedText())
+}
+
+// Cut copies the selected text and removes it
+func (t *TextBox) Cut() {
+	if t.password || t.caret == t.anchor {
+		return
+	}
+	t.Copy()
+	t.typing = false
+	t.Insert("")
+}
+
+// Paste replaces the selection with the text in the clipboard
+func (t *TextBox) Paste() {
+	t.typing = false
+	t.Insert(components.GetClipboard().ClipboardText())
+}
+
+func (t *TextBox) state() textBoxState {
+	return textBoxState{t.value, t.caret, t.anchor}
+}
+
+func (t *TextBox) restore(state textBoxState) {
+	t.value, t.caret, t.anchor = state.value, state.caret, state.anchor
+	t.typing = false
+	t.updateDisplay()
+	t.changed()
+}
+
+// State returns the text and selection, see components.StateHolder
+func (t *TextBox) State() interface{} {
+	return t.state()
+}
+
+// SetState restores the text and selection, without calling OnChange
+func (t *TextBox) SetState(state interface{}) {
+	if state, ok := state.(textBoxState); ok {
+		t.value, t.caret, t.anchor = state.value, state.caret, state.anchor
+		t.undo, t.redo, t.typing = nil, nil, false
+		t.updateDisplay()
+	}
+}
+
+// edit changes the text as a single undoable step and moves the caret
+func (t *TextBox) edit(value string, caret int) {
+	if !t.typing || len(t.undo) == 0 {
+		t.undo = append(t.undo, t.state())
+		if len(t.undo) > maxUndoSteps {
+			t.undo = t.undo[1:]
+		}
+	}
+	t.redo = nil
+	t.value = value
+	t.caret, t.anchor = caret, caret
+	t.updateDisplay()
+	t.changed()
+}
+
+func (t *TextBox) changed() {
+	if t.OnChange != nil {
+		t.OnChange(t.value)
+	}
+	notifyChange(t)
+}
+
+// limit cuts text to be inserted so that the whole text doesn't go over the maximum length,
+// replaced is the part of the current text that is being replaced
+func (t *TextBox) limit(str, replaced string) string {
+	if t.maxLength <= 0 {
+		return str
+	}
+	free := t.maxLength - utf8.RuneCountInString(t.value) + utf8.RuneCountInString(replaced)
+	if free <= 0 {
+		return ""
+	}
+	for i := range str {
+		if free == 0 {
+			return str[:i]
+		}
+		free--
+	}
+	return str
+}
+
+func (t *TextBox) clamp(index int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > len(t.value) {
+		return len(t.value)
+	}
+	// Never stop in the middle of a character
+	for index > 0 && index < len(t.value) && !utf8.RuneStart(t.value[index]) {
+		index--
+	}
+	return index
+}
+
+// updateDisplay sets what the underlying text component shows
+func (t *TextBox) updateDisplay() {
+	switch {
+	case t.value == "" && t.placeholder != "":
+		t.Text.SetRuns([]components.TextRun{{
+			Text:  t.placeholder,
+			Style: components.TextStyle{Color: DefaultPlaceholderColor},
+		}})
+	case t.password:
+		t.Text.SetText(strings.Repeat(string(DefaultPasswordCharacter), utf8.RuneCountInString(t.value)))
+	default:
+		t.Text.SetText(t.value)
+	}
+	t.SetRedraw()
+}
+
+// toDisplay converts a byte offset in the value to one in the displayed text
+func (t *TextBox) toDisplay(index int) int {
+	if t.value == "" {
+		return 0
+	}
+	if t.password {
+		return utf8.RuneCountInString(t.value[:index]) * utf8.RuneLen(DefaultPasswordCharacter)
+	}
+	return index
+}
+
+// fromDisplay converts a byte offset in the displayed text to one in the value
+func (t *TextBox) fromDisplay(index int) int {
+	if t.value == "" {
+		return 0
+	}
+	if t.password {
+		count := index / utf8.RuneLen(DefaultPasswordCharacter)
+		for i := range t.value {
+			if count == 0 {
+				return i
+			}
+			count--
+		}
+		return len(t.value)
+	}
+	return t.clamp(index)
+}
+
+// moveCaret moves the caret, also moving the selection anchor unless selecting
+func (t *TextBox) moveCaret(index int, selecting bool) {
+	t.caret = t.clamp(index)
+	if !selecting {
+		t.anchor = t.caret
+	}
+	t.typing = false
+	t.SetRedraw()
+}
+
+func (t *TextBox) onMouseDown(ev *components.Event) {
+	if ev.Button != input.MouseButtonLeft {
+		return
+	}
+	t.moveCaret(t.fromDisplay(t.IndexAt(ev.Position)), ev.Modifiers.Has(input.ModShift))
+	t.selecting = true
+}
+
+func (t *TextBox) onMouseMove(ev *components.Event) {
+	if t.selecting {
+		t.moveCaret(t.fromDisplay(t.IndexAt(ev.Position)), true)
+	}
+}
+
+func (t *TextBox) onMouseUp(ev *components.Event) {
+	if ev.Button == input.MouseButtonLeft {
+		t.selecting = false
+	}
+}
+
+// onChar inserts typed characters. Character events never come from shortcuts (those are
+// handled in onKeyDown), but can have modifiers: AltGr is reported as Control+Alt.
+func (t *TextBox) onChar(ev *components.Event) {
+	if !t.focused {
+		return
+	}
+	t.Insert(string(ev.Char))
+	// Typing a word is undone all at once, whitespace ends the word
+	t.typing = !unicode.IsSpace(ev.Char)
+	ev.StopPropagation()
+}
+
+func (t *TextBox) onKeyDown(ev *components.Event) {
+	if !t.focused {
+		return
+	}
+	shift := ev.Modifiers.Has(input.ModShift)
+	ctrl := ev.Modifiers.Has(input.ModControl) || ev.Modifiers.Has(input.ModSuper)
+	start, end := t.Selection()
+
+	switch ev.Key {
+	case input.KeyLeft:
+		switch {
+		case ctrl:
+			t.moveCaret(previousWord(t.value, t.caret), shift)
+		case start != end && !shift:
+			t.moveCaret(start, false)
+		default:
+			_, size := utf8.DecodeLastRuneInString(t.value[:t.caret])
+			t.moveCaret(t.caret-size, shift)
+		}
+	case input.KeyRight:
+		switch {
+		case ctrl:
+			t.moveCaret(nextWord(t.value, t.caret), shift)
+		case start != end && !shift:
+			t.moveCaret(end, false)
+		default:
+			_, size := utf8.DecodeRuneInString(t.value[t.caret:])
+			t.moveCaret(t.caret+size, shift)
+		}
+	case input.KeyUp:
+		t.moveLine(-1, shift)
+	case input.KeyDown:
+		t.moveLine(1, shift)
+	case input.KeyHome:
+		if ctrl || !t.multiLine {
+			t.moveCaret(0, shift)
+		} else {
+			layout := t.TextLayout()
+			t.moveCaret(t.fromDisplay(layout.Lines[layout.LineAt(t.toDisplay(t.caret))].TextStart), shift)
+		}
+	case input.KeyEnd:
+		if ctrl || !t.multiLine {
+			t.moveCaret(len(t.value), shift)
+		} else {
+			layout := t.TextLayout()
+			t.moveCaret(t.fromDisplay(layout.Lines[layout.LineAt(t.toDisplay(t.caret))].TextEnd), shift)
+		}
+	case input.KeyBackspace:
+		if start == end {
+			if ctrl {
+				start = previousWord(t.value, t.caret)
+			} else {
+				_, size := utf8.DecodeLastRuneInString(t.value[:t.caret])
+				start = t.caret - size
+			}
+		}
+		if start != end {
+			t.typing = false
+			t.edit(t.value[:start]+t.value[end:], start)
+		}
+	case input.KeyDelete:
+		if start == end {
+			if ctrl {
+				end = nextWord(t.value, t.caret)
+			} else {
+				_, size := utf8.DecodeRuneInString(t.value[t.caret:])
+				end = t.caret + size
+			}
+		}
+		if start != end {
+			t.typing = false
+			t.edit(t.value[:start]+t.value[end:], start)
+		}
+	case input.KeyEnter:
+		if !t.multiLine {
+			return
+		}
+		t.typing = false
+		t.Insert("\n")
+	case input.KeyA:
+		if !ctrl {
+			return
+		}
+		t.Select(0, len(t.value))
+	case input.KeyC:
+		if !ctrl {
+			return
+		}
+		t.Copy()
+	case input.KeyX:
+		if !ctrl {
+			return
+		}
+		t.Cut()
+	case input.KeyV:
+		if !ctrl {
+			return
+		}
+		t.Paste()
+	case input.KeyZ:
+		if !ctrl {
+			return
+		}
+		if shift {
+			t.Redo()
+		} else {
+			t.Undo()
+		}
+	case input.KeyY:
+		if !ctrl {
+			return
+		}
+		t.Redo()
+	default:
+		return
+	}
+	ev.StopPropagation()
+}
+
+// moveLine moves the caret to the line above or below, keeping it as close as possible
+// to where it is horizontally
+func (t *TextBox) moveLine(direction int, selecting bool) {
+	layout := t.TextLayout()
+	caret := layout.CaretAt(t.toDisplay(t.caret))
+	line := caret.Line + direction
+	switch {
+	case line < 0:
+		t.moveCaret(0, selecting)
+	case line >= len(layout.Lines):
+		t.moveCaret(len(t.value), selecting)
+	default:
+		target := layout.Lines[line]
+		t.moveCaret(t.fromDisplay(layout.IndexAt(caret.X, target.Y+target.Height/2)), selecting)
+	}
+}
+
+// previousWord returns where the word before an offset starts
+func previousWord(str string, index int) int {
+	runes := []rune(str[:index])
+	i := len(runes)
+	for i > 0 && unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	return len(string(runes[:i]))
+}
+
+// nextWord returns where the word after an offset ends
+func nextWord(str string, index int) int {
+	runes := []rune(str[index:])
+	i := 0
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	for i < len(runes) && !unicode.IsSpace(runes[i]) {
+		i++
+	}
+	return index + len(string(runes[:i]))
+}
+
+// singleLine replaces newlines with spaces
+func singleLine(str string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(str)
+}
+
+// Draw draws the background, the selection, the text and the caret
+func (t *TextBox) Draw(r render.Renderer) {
+	if t.Background != nil {
+		r.FillRect(t.Bounds().Rect(), t.Background)
+	}
+	if t.focused && t.value != "" {
+		start, end := t.Selection()
+		for _, rect := range t.SelectionRects(t.toDisplay(start), t.toDisplay(end)) {
+			r.FillRect(rect, t.SelectionColor)
+		}
+	}
+	t.Text.Draw(r)
+	if t.focused {
+		r.FillRect(t.CaretRect(t.toDisplay(t.caret)), t.CaretColor)
+	}
+	t.Text.ClearFlags()
+}
+
+// ShouldDraw returns whether the text box needs to be re-drawn
+func (t *TextBox) ShouldDraw() bool {
+	return t.Text.ShouldDraw() || t.Base.ShouldDraw()
+}
+
+func (t *TextBox) String() string {
+	return fmt.Sprintf(`<TextBox Text="%s" Placeholder="%s" Password="%t" MaxLength="%d" MultiLine="%t" FontFace="%s" FontSize="%g" Color="%s" Background="%s" />`,
+		escapeAttribute(t.value), escapeAttribute(t.placeholder), t.password, t.maxLength, t.multiLine,
+		escapeAttribute(t.FontFace()), t.FontSize(), utils.ToHexColor(t.Color()), utils.ToHexColor(t.Background))
+}
+
+// SetAttribute changes the text (Text is the value), Placeholder, Password, MaxLength or a
+// text attribute, for bindings
+func (t *TextBox) SetAttribute(name string, value components.Attribute) error {
+	switch name {
+	case "Text":
+		// Don't move the caret if nothing changed (eg. two-way bindings updating back)
+		if value.String() != t.value {
+			t.SetValue(value.String())
+		}
+	case "Placeholder":
+		t.SetPlaceholder(value.String())
+	case "Password":
+		password, err := value.Bool()
+		if err != nil {
+			return errors.New("Password must be either true or false")
+		}
+		t.SetPassword(password)
+	case "MaxLength":
+		maxLength, err := value.Int()
+		if err != nil || maxLength < 0 {
+			return errors.New("MaxLength must be a positive integer")
+		}
+		t.SetMaxLength(maxLength)
+	default:
+		return setTextAttribute(&t.Text, name, value)
+	}
+	return nil
+}
+
+// GetAttribute returns Text, which the user can change
+func (t *TextBox) GetAttribute(name string) (components.Attribute, bool) {
+	if name == "Text" {
+		return components.Attribute(t.value), true
+	}
+	return "", false
+}
+
+func makeTextBox(list components.AttributeList) (components.Component, error) {
+	box := MakeTextBox()
+	err := parseTextAttributes(&box.Text, list)
+	if err != nil {
+		return nil, err
+	}
+
+	multiLine, err := list.Get("MultiLine", "false").Bool()
+	if err != nil {
+		return nil, errors.New("MultiLine must be either true or false")
+	}
+	box.SetMultiLine(multiLine)
+	if _, ok := list["Wrap"]; !ok {
+		box.SetWrap(multiLine)
+	}
+
+	password, err := list.Get("Password", "false").Bool()
+	if err != nil {
+		return nil, errors.New("Password must be either true or false")
+	}
+	box.SetPassword(password)
+
+	maxLength, err := list.Get("MaxLength", "0").Int()
+	if err != nil || maxLength < 0 {
+		return nil, errors.New("MaxLength must be a positive integer")
+	}
+	box.SetMaxLength(maxLength)
+
+	if col, ok := list["Background"]; ok {
+		hcol, err := col.Color()
+		if err != nil {
+			return nil, err
+		}
+		box.Background = hcol
+	}
+
+	box.SetPlaceholder(list.Get("Placeholder", "").String())
+	// Text is the value, what's displayed depends on the other settings
+	box.SetValue(list.Get("Text", "").String())
+	return box, nil
+}
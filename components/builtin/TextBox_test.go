@@ -0,0 +1,87 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+)
+
+// typeInto sends the events a user typing would send to a focused text box
+func typeInto(box *TextBox, str string) {
+	for _, chr := range str {
+		box.HandleEvent(&components.Event{Type: components.EventChar, Char: chr, Phase: components.PhaseTarget})
+	}
+}
+
+func pressKey(box *TextBox, key input.Key, mods input.Modifier) {
+	box.HandleEvent(&components.Event{Type: components.EventKeyDown, Key: key, Modifiers: mods, Phase: components.PhaseTarget})
+}
+
+func TestTextBoxEditing(t *testing.T) {
+	box := MakeTextBox()
+	box.SetFocused(true)
+
+	typeInto(box, "hello world")
+	pressKey(box, input.KeyLeft, input.ModControl)
+	pressKey(box, input.KeyEnd, input.ModShift)
+	if box.SelectedText() != "world" {
+		t.Fatalf("expected \"world\" to be selected, got %q", box.SelectedText())
+	}
+
+	// Cut and paste it at the start
+	pressKey(box, input.KeyX, input.ModControl)
+	pressKey(box, input.KeyHome, 0)
+	pressKey(box, input.KeyV, input.ModControl)
+	if box.Value() != "worldhello " {
+		t.Fatalf("unexpected value after cut and paste: %q", box.Value())
+	}
+
+	// Undo paste, cut and the two typed words
+	expected := []string{"hello ", "hello world", "hello ", ""}
+	for _, value := range expected {
+		pressKey(box, input.KeyZ, input.ModControl)
+		if box.Value() != value {
+			t.Fatalf("expected %q after undo, got %q", value, box.Value())
+		}
+	}
+	pressKey(box, input.KeyY, input.ModControl)
+	if box.Value() != "hello " {
+		t.Fatalf("expected redo to bring back \"hello \", got %q", box.Value())
+	}
+}
+
+func TestTextBoxLimits(t *testing.T) {
+	box := MakeTextBox()
+	box.SetFocused(true)
+	box.SetMaxLength(4)
+	box.SetPassword(true)
+
+	typeInto(box, "secret")
+	if box.Value() != "secr" {
+		t.Fatalf("expected value to be cut to 4 characters, got %q", box.Value())
+	}
+	if box.Content() != "••••" {
+		t.Fatalf("expected masked content, got %q", box.Content())
+	}
+
+	// Single line boxes turn newlines into spaces
+	box = MakeTextBox()
+	box.SetValue("a\nb")
+	if box.Value() != "a b" {
+		t.Fatalf("expected newline to be replaced, got %q", box.Value())
+	}
+}
+
+func TestTextBoxAltGr(t *testing.T) {
+	box := MakeTextBox()
+	box.SetFocused(true)
+
+	// AltGr comes as Control+Alt on some systems
+	for _, chr := range "@€{" {
+		box.HandleEvent(&components.Event{Type: components.EventChar, Char: chr, Modifiers: input.ModControl | input.ModAlt, Phase: components.PhaseTarget})
+	}
+	if box.Value() != "@€{" {
+		t.Fatalf("expected characters typed with AltGr, got %q", box.Value())
+	}
+}
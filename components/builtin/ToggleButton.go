@@ -0,0 +1,118 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// ToggleButton is a button that stays pressed (checked) until clicked again
+type ToggleButton struct {
+	Button
+
+	// CheckedColor is the background color when checked, unless disabled
+	CheckedColor color.Color
+	// OnChange is called when the button is checked or unchecked by the user
+	OnChange func(checked bool)
+
+	checked bool
+}
+
+// MakeToggleButton creates an unchecked toggle button with a text label
+func MakeToggleButton(label string) *ToggleButton {
+	button := &ToggleButton{CheckedColor: DefaultAccentColor}
+	button.init(label)
+	button.activate = func() {
+		button.SetChecked(!button.checked)
+		if button.OnChange != nil {
+			button.OnChange(button.checked)
+		}
+		notifyChange(button)
+	}
+	return button
+}
+
+// SetChecked sets whether the button is checked, without calling OnChange
+func (b *ToggleButton) SetChecked(checked bool) {
+	b.checked = checked
+	b.SetRedraw()
+}
+
+// Checked returns whether the button is checked
+func (b *ToggleButton) Checked() bool {
+	return b.checked
+}
+
+// State returns whether the button is checked, see components.StateHolder
+func (b *ToggleButton) State() interface{} {
+	return b.checked
+}
+
+// SetState restores whether the button is checked
+func (b *ToggleButton) SetState(state interface{}) {
+	if checked, ok := state.(bool); ok {
+		b.SetChecked(checked)
+	}
+}
+
+// Draw draws the button, with the checked color as background when checked
+func (b *ToggleButton) Draw(r render.Renderer) {
+	colors := b.Colors
+	if b.checked {
+		colors.Normal, colors.Hovered, colors.Pressed = b.CheckedColor, b.CheckedColor, b.CheckedColor
+	}
+	b.drawWithColors(r, colors)
+}
+
+// SetAttribute changes Checked or any Button attribute, for bindings
+func (b *ToggleButton) SetAttribute(name string, value components.Attribute) error {
+	if name != "Checked" {
+		return b.Button.SetAttribute(name, value)
+	}
+	checked, err := value.Bool()
+	if err != nil {
+		return errors.New("Checked must be either true or false")
+	}
+	b.SetChecked(checked)
+	return nil
+}
+
+// GetAttribute returns Checked, which the user can change
+func (b *ToggleButton) GetAttribute(name string) (components.Attribute, bool) {
+	if name == "Checked" {
+		return formatBool(b.checked), true
+	}
+	return "", false
+}
+
+func (b *ToggleButton) String() string {
+	return fmt.Sprintf(`<ToggleButton Text="%s" FontFace="%s" FontSize="%g" Color="%s" Padding="%s" Disabled="%t" Checked="%t" CheckedBackground="%s" %s />`,
+		escapeAttribute(b.Content()), escapeAttribute(b.FontFace()), b.FontSize(), utils.ToHexColor(b.Color()),
+		b.Padding(), b.Disabled(), b.checked, utils.ToHexColor(b.CheckedColor), formatStateColors(b.Colors))
+}
+
+func makeToggleButton(list components.AttributeList) (components.Component, error) {
+	button := MakeToggleButton("")
+	if err := parseButtonAttributes(&button.Button, list); err != nil {
+		return nil, err
+	}
+
+	checked, err := list.Get("Checked", "false").Bool()
+	if err != nil {
+		return nil, errors.New("Checked must be either true or false")
+	}
+	button.SetChecked(checked)
+
+	if col, ok := list["CheckedBackground"]; ok {
+		hcol, err := col.Color()
+		if err != nil {
+			return nil, err
+		}
+		button.CheckedColor = hcol
+	}
+	return button, nil
+}
@@ -0,0 +1,97 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+// Default colors for interactive controls
+var (
+	DefaultControlColors = components.StateColors{
+		Normal:   color.RGBA{0x3a, 0x3a, 0x3a, 0xff},
+		Hovered:  color.RGBA{0x4a, 0x4a, 0x4a, 0xff},
+		Pressed:  color.RGBA{0x2a, 0x2a, 0x2a, 0xff},
+		Disabled: color.RGBA{0x2a, 0x2a, 0x2a, 0x80},
+	}
+	DefaultAccentColor   = color.RGBA{0x33, 0x66, 0xcc, 0xff}
+	DefaultDisabledColor = color.RGBA{0x80, 0x80, 0x80, 0xff}
+)
+
+// insetRect shrinks a rectangle by the same amount of pixels on every side
+func insetRect(r render.Renderer, rect render.Rect, pixels float32) render.Rect {
+	size := r.Size()
+	w, h := pixels/float32(size.X), pixels/float32(size.Y)
+	return render.Rect{X: rect.X + w, Y: rect.Y + h, Width: rect.Width - 2*w, Height: rect.Height - 2*h}
+}
+
+// parseControlAttributes applies the attributes shared by all interactive controls
+func parseControlAttributes(state *components.ControlState, list components.AttributeList) error {
+	return setDisabled(state, list.Get("Disabled", "false"))
+}
+
+func setDisabled(state *components.ControlState, value components.Attribute) error {
+	disabled, err := value.Bool()
+	if err != nil {
+		return errors.New("Disabled must be either true or false")
+	}
+	state.SetDisabled(disabled)
+	return nil
+}
+
+// notifyChange sends EventChange to a control the user changed, for two-way bindings
+func notifyChange(c components.Component) {
+	c.HandleEvent(&components.Event{Type: components.EventChange, Phase: components.PhaseTarget, Target: c, CurrentTarget: c})
+}
+
+// errAttributeNotSettable means an attribute can only be set when loading, not by bindings
+func errAttributeNotSettable(name string) error {
+	return fmt.Errorf("%s can't be changed after loading", name)
+}
+
+// formatBool and formatFloat format attributes returned by GetAttribute
+func formatBool(value bool) components.Attribute {
+	return components.Attribute(strconv.FormatBool(value))
+}
+
+func formatFloat(value float64) components.Attribute {
+	return components.Attribute(strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// parseStateColors reads per-state colors: Background, HoverBackground, PressedBackground
+// and DisabledBackground
+func parseStateColors(list components.AttributeList, def components.StateColors) (components.StateColors, error) {
+	colors := def
+	fields := []struct {
+		name  string
+		value *color.Color
+	}{
+		{"Background", &colors.Normal},
+		{"HoverBackground", &colors.Hovered},
+		{"PressedBackground", &colors.Pressed},
+		{"DisabledBackground", &colors.Disabled},
+	}
+	for _, field := range fields {
+		if attr, ok := list[field.name]; ok {
+			col, err := attr.Color()
+			if err != nil {
+				return colors, err
+			}
+			*field.value = col
+		}
+	}
+	return colors, nil
+}
+
+// formatStateColors writes per-state colors as attributes, see parseStateColors
+func formatStateColors(colors components.StateColors) string {
+	return `Background="` + utils.ToHexColor(colors.Get(components.StateNormal)).String() +
+		`" HoverBackground="` + utils.ToHexColor(colors.Get(components.StateHovered)).String() +
+		`" PressedBackground="` + utils.ToHexColor(colors.Get(components.StatePressed)).String() +
+		`" DisabledBackground="` + utils.ToHexColor(colors.Get(components.StateDisabled)).String() + `"`
+}
@@ -0,0 +1,58 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+)
+
+func TestRadioButtonGroups(t *testing.T) {
+	root := &Page{}
+	a, b := MakeRadioButton("a", "first"), MakeRadioButton("b", "first")
+	other := MakeRadioButton("c", "second")
+	root.AppendChild(a)
+	root.AppendChild(b)
+	root.AppendChild(other)
+
+	changes := 0
+	a.OnChange = func(bool) { changes++ }
+
+	other.Click()
+	a.Click()
+	b.Click()
+	if a.Checked() || !b.Checked() || !other.Checked() {
+		t.Fatalf("unexpected states: a=%t b=%t c=%t", a.Checked(), b.Checked(), other.Checked())
+	}
+	if changes != 2 || a.Selected() != b {
+		t.Fatalf("expected a to be checked then unchecked, got %d changes", changes)
+	}
+
+	// Disabled controls ignore clicks
+	a.SetDisabled(true)
+	a.Click()
+	if a.Checked() {
+		t.Fatal("disabled radio button was checked")
+	}
+}
+
+func TestSliderSnap(t *testing.T) {
+	slider := MakeSlider(0, 10, 2.5)
+	var got float64
+	slider.OnChange = func(value float64) { got = value }
+
+	slider.SetFocused(true)
+	slider.HandleEvent(&components.Event{Type: components.EventKeyDown, Key: input.KeyRight, Phase: components.PhaseTarget})
+	if got != 2.5 {
+		t.Fatalf("expected right arrow to move by a step, got %g", got)
+	}
+
+	slider.SetValue(6.1)
+	if slider.Value() != 5 {
+		t.Fatalf("expected value to snap to 5, got %g", slider.Value())
+	}
+	slider.SetValue(42)
+	if slider.Value() != 10 {
+		t.Fatalf("expected value to be clamped to 10, got %g", slider.Value())
+	}
+}
@@ -5,8 +5,20 @@ import "github.com/hamcha/youi/components"
 const Namespace = "https://yuml.ovo.ovh/schema/components/1.0"
 
 var AllComponents = map[string]components.ComponentProvider{
-	"Page":   makePage,
-	"Canvas": makeCanvas,
-	"Image":  makeImage,
-	"Label":  makeLabel,
+	"Page":    makePage,
+	"Canvas":  makeCanvas,
+	"Image":   makeImage,
+	"Label":   makeLabel,
+	"Stack":   makeStack,
+	"Grid":    makeGrid,
+	"TextBox": makeTextBox,
+
+	"Button":       makeButton,
+	"ToggleButton": makeToggleButton,
+	"CheckBox":     makeCheckBox,
+	"RadioButton":  makeRadioButton,
+	"Slider":       makeSlider,
+	"ScrollView":   makeScrollView,
+	"ListView":     makeListView,
+	"ProgressBar":  makeProgressBar,
 }
@@ -0,0 +1,40 @@
+package builtin
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/hamcha/youi/utils"
+)
+
+// addSettings adds a parent's per-child settings (eg. Stack.Grow="1") to the opening
+// tag of a child's YUML representation
+func addSettings(str, parent string, settings []string) string {
+	if len(settings) == 0 {
+		return str
+	}
+
+	// Find where the element name ends
+	end := strings.IndexAny(str, " />")
+	if end < 0 {
+		return str
+	}
+
+	attrs := ""
+	for _, setting := range settings {
+		attrs += " " + parent + "." + setting
+	}
+	return str[:end] + attrs + str[end:]
+}
+
+func indent(str string) string {
+	return utils.IndentStrings(str, 1)
+}
+
+// escapeAttribute escapes a string so it can be used as a YUML attribute value
+func escapeAttribute(str string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(str))
+	return buf.String()
+}
@@ -1,7 +1,16 @@
 package components
 
 import (
+	"errors"
 	"strconv"
+	"strings"
+
+	"github.com/hamcha/youi/utils"
+)
+
+// Attribute errors
+var (
+	ErrInvalidInsets = errors.New("insets must be one, two or four comma-separated numbers")
 )
 
 // Attribute is a single componment attribute
@@ -13,6 +22,21 @@ type AttributeList map[string]Attribute
 // ComponentProvider is a function that takes a list of attributes and creates a component with the attributes applied
 type ComponentProvider func(AttributeList) (Component, error)
 
+// ChildSettingsHandler is implemented by containers that accept per-child settings,
+// such as <Child Stack.Grow="1" /> inside a Stack
+type ChildSettingsHandler interface {
+	SetChildSettings(child Component, settings AttributeList) error
+}
+
+// Template makes a new copy of a component tree, such as the one described by an element
+type Template func() (Component, error)
+
+// TemplateHandler is implemented by containers that take their YUML child as a template to
+// make copies of later (eg. one per list item) instead of as a child
+type TemplateHandler interface {
+	SetTemplate(Template) error
+}
+
 //
 // Functions to easily convert attributes to their target types
 //
@@ -34,6 +58,40 @@ func (a Attribute) Int() (int, error) {
 	return ret, err
 }
 
+// Bool tries to parse an attribute as a boolean (true/false, 1/0)
+func (a Attribute) Bool() (bool, error) {
+	return strconv.ParseBool(string(a))
+}
+
+// Color tries to parse an attribute as a hex color (#RGB, #RRGGBB or #RRGGBBAA)
+func (a Attribute) Color() (utils.HexColor, error) {
+	return utils.ParseHexColor(string(a))
+}
+
+// Insets tries to parse an attribute as insets, either as a single value for all sides,
+// two values (horizontal, vertical) or four values (left, top, right, bottom)
+func (a Attribute) Insets() (Insets, error) {
+	parts := strings.Split(string(a), ",")
+	values := make([]float32, len(parts))
+	for i, part := range parts {
+		val, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return Insets{}, err
+		}
+		values[i] = float32(val)
+	}
+
+	switch len(values) {
+	case 1:
+		return Insets{values[0], values[0], values[0], values[0]}, nil
+	case 2:
+		return Insets{values[0], values[1], values[0], values[1]}, nil
+	case 4:
+		return Insets{values[0], values[1], values[2], values[3]}, nil
+	}
+	return Insets{}, ErrInvalidInsets
+}
+
 // Get returns either the requested attribute or a default value
 func (a AttributeList) Get(name string, def string) Attribute {
 	attr, ok := a[name]
@@ -0,0 +1,175 @@
+package youi
+
+import (
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/input"
+)
+
+// inputState keeps track of the mouse between events, since GLFW only sends deltas
+type inputState struct {
+	cursor    components.Position
+	modifiers input.Modifier
+	// pressed holds the components mouse buttons were pressed on, they receive all mouse
+	// events until the button is released (eg. to keep dragging outside of them)
+	pressed map[input.MouseButton]components.Component
+	// hovered is the topmost component under the cursor
+	hovered components.Component
+}
+
+func (f *Form) bindEvents() {
+	f.input.pressed = make(map[input.MouseButton]components.Component)
+
+	f.window.SetCursorCallback(f.onCursorMove)
+	f.window.SetButtonCallback(f.onMouseButton)
+	f.window.SetScrollCallback(f.onScroll)
+	f.window.SetKeyCallback(f.onKey)
+	f.window.SetCharCallback(f.onChar)
+}
+
+// toRelative converts a window position (in pixels) to root-relative coordinates
+func (f *Form) toRelative(x, y float64) components.Position {
+	size := f.Root.Bounds().Size
+	if size.Width == 0 || size.Height == 0 {
+		return components.Position{}
+	}
+	return components.Position{
+		X: float32(x) / size.Width,
+		Y: float32(y) / size.Height,
+	}
+}
+
+// dispatchMouse sends a mouse event to whatever component is under the cursor and returns it
+func (f *Form) dispatchMouse(ev *components.Event) components.Component {
+	target := components.HitTest(f.Root, f.input.cursor)
+	f.dispatchMouseTo(target, ev)
+	return target
+}
+
+// dispatchMouseTo sends a mouse event to a specific component
+func (f *Form) dispatchMouseTo(target components.Component, ev *components.Event) {
+	ev.Position = f.input.cursor
+	ev.Modifiers = f.input.modifiers
+	components.DispatchEvent(f.Root, target, ev)
+}
+
+// captured returns the component a mouse button is being held on, if any
+func (f *Form) captured() components.Component {
+	for _, cmp := range f.input.pressed {
+		if components.PathTo(f.Root, cmp) != nil {
+			return cmp
+		}
+	}
+	return nil
+}
+
+// updateHover sends enter and leave events if the component under the cursor changed
+func (f *Form) updateHover() {
+	target := components.HitTest(f.Root, f.input.cursor)
+	if target == f.input.hovered {
+		return
+	}
+	if f.input.hovered != nil {
+		f.dispatchMouseTo(f.input.hovered, &components.Event{Type: components.EventMouseLeave})
+	}
+	f.input.hovered = target
+	f.dispatchMouseTo(target, &components.Event{Type: components.EventMouseEnter})
+}
+
+// dispatchKey sends a keyboard event to the focused component, or the root if there is none
+func (f *Form) dispatchKey(ev *components.Event) {
+	ev.Position = f.input.cursor
+	target := components.Component(f.Root)
+	if focused := f.Focused(); focused != nil {
+		target = focused
+	}
+	components.DispatchEvent(f.Root, target, ev)
+}
+
+func (f *Form) onCursorMove(x, y float64) {
+	f.input.cursor = f.toRelative(x, y)
+	f.updateHover()
+
+	ev := &components.Event{
+		Type: components.EventMouseMove,
+	}
+	if captured := f.captured(); captured != nil {
+		f.dispatchMouseTo(captured, ev)
+		return
+	}
+	f.dispatchMouse(ev)
+}
+
+func (f *Form) onMouseButton(button input.MouseButton, action input.Action, mods input.Modifier) {
+	f.input.modifiers = mods
+
+	switch action {
+	case input.Press:
+		f.focusAt(components.HitTest(f.Root, f.input.cursor))
+		f.input.pressed[button] = f.dispatchMouse(&components.Event{
+			Type:   components.EventMouseDown,
+			Button: button,
+		})
+	case input.Release:
+		// Releases go to where the button was pressed, even if the cursor moved away
+		target := components.HitTest(f.Root, f.input.cursor)
+		pressed, ok := f.input.pressed[button]
+		delete(f.input.pressed, button)
+		up := &components.Event{
+			Type:   components.EventMouseUp,
+			Button: button,
+		}
+		if ok && components.PathTo(f.Root, pressed) != nil {
+			f.dispatchMouseTo(pressed, up)
+		} else {
+			f.dispatchMouseTo(target, up)
+		}
+
+		// A click is a press and release on the same component
+		if ok && pressed == target {
+			f.dispatchMouse(&components.Event{
+				Type:   components.EventClick,
+				Button: button,
+			})
+		}
+	}
+}
+
+func (f *Form) onScroll(dx, dy float64) {
+	f.dispatchMouse(&components.Event{
+		Type:    components.EventScroll,
+		ScrollX: float32(dx),
+		ScrollY: float32(dy),
+	})
+}
+
+func (f *Form) onKey(key input.Key, action input.Action, mods input.Modifier) {
+	f.input.modifiers = mods
+
+	ev := &components.Event{
+		Type:      components.EventKeyDown,
+		Key:       key,
+		Modifiers: mods,
+		Repeat:    action == input.Repeat,
+	}
+	if action == input.Release {
+		ev.Type = components.EventKeyUp
+	}
+	f.dispatchKey(ev)
+
+	// Tab moves focus, unless the focused component used it
+	if ev.Type == components.EventKeyDown && key == input.KeyTab && !ev.Stopped() {
+		if mods.Has(input.ModShift) {
+			f.FocusPrevious()
+		} else {
+			f.FocusNext()
+		}
+	}
+}
+
+func (f *Form) onChar(char rune) {
+	f.dispatchKey(&components.Event{
+		Type:      components.EventChar,
+		Char:      char,
+		Modifiers: f.input.modifiers,
+	})
+}
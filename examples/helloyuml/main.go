@@ -39,6 +39,9 @@ func main() {
 	<Canvas X="10" Y="10" Width="100" Height="100">
 		<Image Path="images/hello.png" />
 	</Canvas>
+	<Canvas X="10" Y="120" Width="200" Height="30">
+		<Label Text="Hello from YUML" FontSize="20" Color="#ffcc00" VerticalAlign="Center" />
+	</Canvas>
 </Page>`
 
 	form := youi.MakeForm(window)
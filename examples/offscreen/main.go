@@ -0,0 +1,44 @@
+package main
+
+import (
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/hamcha/youi"
+	"github.com/hamcha/youi/software"
+	"github.com/hamcha/youi/utils"
+
+	// Import example data
+	_ "github.com/hamcha/youi/examples/example_data"
+)
+
+func main() {
+	// No window or OpenGL context needed, everything is drawn into an image
+	renderer := software.MakeRenderer(640, 480, utils.HexColor(0x101020ff))
+
+	const src = `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Canvas X="10" Y="10" Width="100" Height="100">
+		<Image Path="images/hello.png" />
+	</Canvas>
+</Page>`
+
+	form := youi.MakeOffscreenForm(renderer)
+	err := form.LoadYUML(strings.NewReader(src))
+	if err != nil {
+		panic(err)
+	}
+
+	form.Draw()
+
+	file, err := os.Create("offscreen.png")
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	err = png.Encode(file, renderer.Image())
+	if err != nil {
+		panic(err)
+	}
+}
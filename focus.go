@@ -0,0 +1,110 @@
+package youi
+
+import (
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/render"
+)
+
+// Focused returns the component with keyboard focus, or nil if there is none
+func (f *Form) Focused() components.Component {
+	if f.focused != nil && components.PathTo(f.Root, f.focused) == nil {
+		// Removed from the tree
+		f.focused = nil
+	}
+	return f.focused
+}
+
+// Focus gives keyboard focus to a component (nil takes it away from the focused one),
+// sending blur and focus events. It returns false if the component can't get focus.
+func (f *Form) Focus(target components.Component) bool {
+	if target != nil && !components.CanFocus(target) {
+		return false
+	}
+	old := f.Focused()
+	if target == old {
+		return true
+	}
+
+	if old != nil {
+		if focusable, ok := old.(components.Focusable); ok {
+			focusable.SetFocused(false)
+		}
+		components.DispatchEvent(f.Root, old, &components.Event{Type: components.EventBlur, Position: f.input.cursor})
+	}
+	f.focused = target
+	if target != nil {
+		if focusable, ok := target.(components.Focusable); ok {
+			focusable.SetFocused(true)
+		}
+		components.DispatchEvent(f.Root, target, &components.Event{Type: components.EventFocus, Position: f.input.cursor})
+	}
+	return true
+}
+
+// FocusNext moves focus to the next component in tab order, see components.FocusOrder
+func (f *Form) FocusNext() {
+	f.moveFocus(1)
+}
+
+// FocusPrevious moves focus to the previous component in tab order
+func (f *Form) FocusPrevious() {
+	f.moveFocus(-1)
+}
+
+func (f *Form) moveFocus(direction int) {
+	order := components.FocusOrder(f.Root)
+	if len(order) == 0 {
+		return
+	}
+
+	// Start from either end if nothing in the tab order has focus
+	current := -1
+	focused := f.Focused()
+	for i, cmp := range order {
+		if cmp == focused {
+			current = i
+			break
+		}
+	}
+	next := 0
+	switch {
+	case current >= 0:
+		next = (current + direction + len(order)) % len(order)
+	case direction < 0:
+		next = len(order) - 1
+	}
+	f.Focus(order[next])
+}
+
+// focusAt gives focus to the closest component to a clicked one (itself or its parents)
+// that can get it, or takes it away from the focused one if there is none
+func (f *Form) focusAt(target components.Component) {
+	// Parent() only returns the embedded Base, the path has the actual components
+	path := components.PathTo(f.Root, target)
+	for i := len(path) - 1; i >= 0; i-- {
+		if components.CanFocus(path[i]) {
+			f.Focus(path[i])
+			return
+		}
+	}
+	f.Focus(nil)
+}
+
+// updateFocusRing gives the ring color to the focused component, if it draws its own ring
+func (f *Form) updateFocusRing() {
+	if drawer, ok := f.Focused().(components.FocusRingDrawer); ok {
+		drawer.SetFocusRingColor(f.FocusRingColor)
+	}
+}
+
+// drawFocusRing draws a ring around the focused component, unless it draws its own
+func (f *Form) drawFocusRing() {
+	focused := f.Focused()
+	if focused == nil || f.FocusRingColor == nil {
+		return
+	}
+	if _, ok := focused.(components.Focusable); ok {
+		return
+	}
+	render.DrawOutline(f.renderer, focused.Bounds().Rect(), f.FocusRingColor, components.FocusRingWidth)
+}
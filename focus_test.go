@@ -0,0 +1,33 @@
+package youi
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/hamcha/youi/components/builtin"
+)
+
+func TestFocusRingColor(t *testing.T) {
+	form := makeTestForm()
+	err := form.LoadYUML(strings.NewReader(`<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Button Id="ok" Text="Ok" />
+</Page>`))
+	if err != nil {
+		t.Fatalf("Could not load YUML: %s", err.Error())
+	}
+	button := form.FindByID("ok").(*builtin.Button)
+	form.Focus(button)
+
+	// Controls that draw their own ring use the form's color
+	form.FocusRingColor = color.RGBA{0xff, 0, 0, 0xff}
+	form.Draw()
+	if button.FocusRingColor() != form.FocusRingColor {
+		t.Errorf("Expected button ring to be %v, got %v", form.FocusRingColor, button.FocusRingColor())
+	}
+	form.FocusRingColor = nil
+	form.Draw()
+	if button.FocusRingColor() != nil {
+		t.Errorf("Expected button ring to be hidden, got %v", button.FocusRingColor())
+	}
+}
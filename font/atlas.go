@@ -0,0 +1,107 @@
+package font
+
+import (
+	"image"
+	"image/draw"
+)
+
+// initialPageSize is the width/height of a newly created atlas page, pages grow up to
+// MaxTextureSize before a new one is started
+const initialPageSize = 256
+
+// AtlasGlyph is where a rasterized glyph sits in the font atlas
+type AtlasGlyph struct {
+	// Face is the index of the font in the fallback chain the glyph was taken from
+	Face int
+	// Page is the index of the atlas page (see Font.Pages) holding the glyph
+	Page int
+	// Bounds is the glyph position inside the page (empty for glyphs with nothing to draw)
+	Bounds image.Rectangle
+	// Offset is where the top-left corner of Bounds goes relative to the pen position on the baseline
+	Offset image.Point
+	// Advance is how much the pen moves after drawing the glyph, in texture pixels
+	Advance float32
+}
+
+// Atlas is a dictionary that maps each font glyph to its position on the texture atlas
+type Atlas map[rune]AtlasGlyph
+
+// Page is a single texture of a font atlas, glyphs are added to it as they are needed
+type Page struct {
+	Image *image.RGBA
+	// Version changes every time the page is modified, renderers use it to know when the
+	// page needs to be uploaded again
+	Version int
+
+	shelves []shelf
+}
+
+// shelf is a row of glyphs inside a page
+type shelf struct {
+	y, height, width int
+}
+
+func makePage() *Page {
+	return &Page{
+		Image: image.NewRGBA(image.Rect(0, 0, initialPageSize, initialPageSize)),
+	}
+}
+
+// place finds room for a box of the given size, growing the page if needed.
+// It returns false if the box doesn't fit even at the maximum size.
+func (p *Page) place(size image.Point) (image.Point, bool) {
+	for {
+		if pos, ok := p.placeOnShelf(size); ok {
+			return pos, true
+		}
+		if !p.grow() {
+			return image.Point{}, false
+		}
+	}
+}
+
+// placeOnShelf puts a box on the first shelf that can take it, or on a new one
+func (p *Page) placeOnShelf(size image.Point) (image.Point, bool) {
+	pageSize := p.Image.Rect.Size()
+
+	// Try existing shelves that are tall enough, but not wastefully so
+	for i := range p.shelves {
+		s := &p.shelves[i]
+		if size.Y <= s.height && size.Y*2 > s.height && s.width+size.X <= pageSize.X {
+			pos := image.Pt(s.width, s.y)
+			s.width += size.X
+			return pos, true
+		}
+	}
+
+	// Open a new shelf below the last one
+	top := 0
+	if len(p.shelves) > 0 {
+		last := p.shelves[len(p.shelves)-1]
+		top = last.y + last.height
+	}
+	if top+size.Y > pageSize.Y || size.X > pageSize.X {
+		return image.Point{}, false
+	}
+	p.shelves = append(p.shelves, shelf{y: top, height: size.Y, width: size.X})
+	return image.Pt(0, top), true
+}
+
+// grow doubles the smaller side of the page, keeping what was already drawn
+func (p *Page) grow() bool {
+	size := p.Image.Rect.Size()
+	switch {
+	case size.X <= size.Y && size.X < MaxTextureSize:
+		size.X *= 2
+	case size.Y < MaxTextureSize:
+		size.Y *= 2
+	default:
+		return false
+	}
+
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	draw.Draw(img, p.Image.Rect, p.Image, image.ZP, draw.Src)
+	p.Image = img
+	p.Version++
+	return true
+}
@@ -0,0 +1,50 @@
+package font
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPagePlace(t *testing.T) {
+	page := makePage()
+	var placed []image.Rectangle
+	for i := 0; i < 200; i++ {
+		size := image.Pt(40+i%7, 50+i%13)
+		pos, ok := page.place(size)
+		if !ok {
+			t.Fatalf("box %d didn't fit", i)
+		}
+		rect := image.Rectangle{Min: pos, Max: pos.Add(size)}
+		if !rect.In(page.Image.Rect) {
+			t.Fatalf("box %d (%s) is outside the page (%s)", i, rect, page.Image.Rect)
+		}
+		for j, other := range placed {
+			if rect.Overlaps(other) {
+				t.Fatalf("box %d (%s) overlaps box %d (%s)", i, rect, j, other)
+			}
+		}
+		placed = append(placed, rect)
+	}
+	if page.Image.Rect.Dx() <= initialPageSize {
+		t.Fatalf("expected page to grow, still %s", page.Image.Rect)
+	}
+}
+
+func TestLazyGlyphs(t *testing.T) {
+	fnt := DefaultFont()
+	if _, ok := fnt.Atlas['λ']; ok {
+		t.Fatal("expected λ not to be rasterized before use")
+	}
+
+	layout := fnt.Layout("αλφα", LayoutOptions{})
+	glyph, ok := fnt.Atlas['λ']
+	if !ok || glyph.Bounds.Empty() {
+		t.Fatal("expected λ to be rasterized after layout")
+	}
+	if layout.Glyphs[1].Atlas.Width != float32(glyph.Bounds.Dx()) {
+		t.Fatalf("laid out glyph doesn't match atlas: %v vs %v", layout.Glyphs[1].Atlas, glyph.Bounds)
+	}
+	if fnt.Texture != fnt.Pages[0].Image {
+		t.Fatal("expected Texture to be the first page")
+	}
+}
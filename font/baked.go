@@ -0,0 +1,212 @@
+package font
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/golang/freetype/truetype"
+
+	"github.com/hamcha/youi/loader"
+)
+
+// Baked fonts are stored as a JSON manifest (NAME.atlas.json) holding glyph metrics and
+// kerning, plus one PNG per atlas page (NAME.atlas0.png, NAME.atlas1.png...).
+// They skip rasterization and distance field generation, which are slow, at startup.
+
+// ErrInvalidBakedFont means a baked font manifest doesn't match its pages
+var ErrInvalidBakedFont = errors.New("baked font manifest is invalid")
+
+// bakedFont is the manifest of a baked font
+type bakedFont struct {
+	// Fonts are the names of the TTF fonts in the chain, used for glyphs that weren't baked
+	Fonts      []string       `json:"fonts"`
+	Size       int            `json:"size"`
+	Ascent     float32        `json:"ascent"`
+	LineHeight float32        `json:"lineHeight"`
+	Pages      []string       `json:"pages"`
+	Glyphs     []bakedGlyph   `json:"glyphs"`
+	Kerning    []bakedKerning `json:"kerning"`
+}
+
+type bakedGlyph struct {
+	Rune    rune    `json:"rune"`
+	Face    int     `json:"face"`
+	Page    int     `json:"page"`
+	X       int     `json:"x"`
+	Y       int     `json:"y"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	OffsetX int     `json:"offsetX"`
+	OffsetY int     `json:"offsetY"`
+	Advance float32 `json:"advance"`
+}
+
+type bakedKerning struct {
+	Left   rune    `json:"left"`
+	Right  rune    `json:"right"`
+	Amount float32 `json:"amount"`
+}
+
+func bakedManifestName(name string) string {
+	return name + ".atlas.json"
+}
+
+func bakedPageName(name string, page int) string {
+	return fmt.Sprintf("%s.atlas%d.png", name, page)
+}
+
+// SaveBaked writes every glyph rasterized so far (see Include), with metrics and kerning,
+// as a baked font in a folder. fontNames are the TTF names (as in LoadFont) of the fonts
+// in the chain, so that missing glyphs can still be generated when loading it.
+func (f *Font) SaveBaked(dir, name string, fontNames []string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	manifest := bakedFont{
+		Fonts:      fontNames,
+		Size:       f.Size,
+		Ascent:     f.ascent,
+		LineHeight: f.lineHeight,
+	}
+
+	// Write pages
+	for index, page := range f.Pages {
+		pageName := bakedPageName(name, index)
+		if err := writePNG(filepath.Join(dir, pageName), page.Image); err != nil {
+			return err
+		}
+		manifest.Pages = append(manifest.Pages, pageName)
+	}
+
+	// Add glyph metrics, sorted so that baking the same font twice gives the same result
+	chars := make([]rune, 0, len(f.Atlas))
+	for chr := range f.Atlas {
+		chars = append(chars, chr)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	for _, chr := range chars {
+		glyph := f.Atlas[chr]
+		manifest.Glyphs = append(manifest.Glyphs, bakedGlyph{
+			Rune:    chr,
+			Face:    glyph.Face,
+			Page:    glyph.Page,
+			X:       glyph.Bounds.Min.X,
+			Y:       glyph.Bounds.Min.Y,
+			Width:   glyph.Bounds.Dx(),
+			Height:  glyph.Bounds.Dy(),
+			OffsetX: glyph.Offset.X,
+			OffsetY: glyph.Offset.Y,
+			Advance: glyph.Advance,
+		})
+	}
+
+	// Add kerning for all pairs of baked glyphs, skipping zeroes
+	for _, left := range chars {
+		for _, right := range chars {
+			if amount := f.kern(left, right, f.Atlas[left], f.Atlas[right]); amount != 0 {
+				manifest.Kerning = append(manifest.Kerning, bakedKerning{left, right, amount})
+			}
+		}
+	}
+
+	file, err := os.Create(filepath.Join(dir, bakedManifestName(name)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewEncoder(file).Encode(manifest)
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return png.Encode(file, img)
+}
+
+// LoadBakedFont loads a baked font from the "fonts" folder of the loader bundle sequence.
+// If the TTF fonts it was made from are available too, glyphs that weren't baked are
+// rasterized when needed, otherwise they are skipped.
+func LoadBakedFont(name string) (*Font, error) {
+	data, err := loader.Bytes("fonts/" + bakedManifestName(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest bakedFont
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Size <= 0 {
+		return nil, ErrInvalidBakedFont
+	}
+
+	f := &Font{
+		Atlas:      make(Atlas),
+		Size:       manifest.Size,
+		kerning:    make(map[[2]rune]float32),
+		ascent:     manifest.Ascent,
+		lineHeight: manifest.LineHeight,
+	}
+
+	// Load pages, marking them as full so new glyphs don't end up over baked ones
+	for _, pageName := range manifest.Pages {
+		img, err := loader.Image("fonts/" + pageName)
+		if err != nil {
+			return nil, err
+		}
+		size := img.Rect.Size()
+		f.Pages = append(f.Pages, &Page{
+			Image:   img,
+			shelves: []shelf{{y: 0, height: size.Y, width: size.X}},
+		})
+	}
+
+	for _, glyph := range manifest.Glyphs {
+		if glyph.Page < 0 || glyph.Page >= len(f.Pages) {
+			return nil, ErrInvalidBakedFont
+		}
+		f.Atlas[glyph.Rune] = AtlasGlyph{
+			Face:    glyph.Face,
+			Page:    glyph.Page,
+			Bounds:  image.Rect(glyph.X, glyph.Y, glyph.X+glyph.Width, glyph.Y+glyph.Height),
+			Offset:  image.Pt(glyph.OffsetX, glyph.OffsetY),
+			Advance: glyph.Advance,
+		}
+	}
+
+	for _, pair := range manifest.Kerning {
+		f.kerning[[2]rune{pair.Left, pair.Right}] = pair.Amount
+	}
+
+	// Attach TTF fonts, if all of them are there
+	var ttfs []*truetype.Font
+	for _, fontName := range manifest.Fonts {
+		ttf, err := loadTTF(fontName)
+		if err != nil {
+			ttfs = nil
+			break
+		}
+		ttfs = append(ttfs, ttf)
+	}
+	if len(ttfs) > 0 {
+		f.TTF = ttfs[0]
+		f.setFaces(ttfs)
+	}
+
+	// Make sure there is room for new glyphs
+	if len(f.Pages) < 1 {
+		f.Pages = append(f.Pages, makePage())
+	}
+	f.Texture = f.Pages[0].Image
+
+	return f, nil
+}
@@ -0,0 +1,52 @@
+package font
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	resources "gopkg.in/cookieo9/resources-go.v2"
+
+	"github.com/hamcha/youi/loader"
+)
+
+func TestBakedFont(t *testing.T) {
+	dir, err := ioutil.TempDir("", "youi-font")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "fonts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bake the default font, without its TTF name so it can't generate new glyphs
+	original := DefaultFont()
+	original.Include('Ω')
+	if err := original.SaveBaked(filepath.Join(dir, "fonts"), "baked", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	oldBundles := loader.BundleSequence
+	loader.BundleSequence = resources.BundleSequence{resources.OpenFS(dir)}
+	defer func() { loader.BundleSequence = oldBundles }()
+
+	baked, err := LoadFont("baked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ClearCache()
+
+	expected := original.Layout("AVΩ", LayoutOptions{})
+	actual := baked.Layout("AVΩ", LayoutOptions{})
+	for i := range expected.Glyphs {
+		if expected.Glyphs[i] != actual.Glyphs[i] {
+			t.Fatalf("glyph %d differs: expected %+v, got %+v", i, expected.Glyphs[i], actual.Glyphs[i])
+		}
+	}
+
+	if baked.HasGlyph('日') {
+		t.Fatal("expected baked font without TTF to only have baked glyphs")
+	}
+}
@@ -0,0 +1,248 @@
+package font
+
+import "golang.org/x/text/unicode/bidi"
+
+// Direction is the base direction of a paragraph
+type Direction int
+
+// Paragraph directions
+const (
+	// DirectionAuto takes the direction from the first strong character (left to right if there is none)
+	DirectionAuto Direction = iota
+	LeftToRight
+	RightToLeft
+)
+
+// Bidirectional text follows the Unicode Bidirectional Algorithm (UAX #9) without explicit
+// embeddings, overrides, isolates and bracket pairs: formatting characters are ignored and
+// brackets are resolved like any other neutral.
+
+// resolveLevels returns the embedding level of every character of a paragraph (odd levels
+// are right to left) and the paragraph level
+func resolveLevels(runes []shapedRune, direction Direction) ([]int, int) {
+	types := make([]bidi.Class, len(runes))
+	for i, chr := range runes {
+		props, _ := bidi.LookupRune(chr.Rune)
+		types[i] = props.Class()
+	}
+
+	// P2, P3: find paragraph level
+	paragraph := 0
+	switch direction {
+	case RightToLeft:
+		paragraph = 1
+	case DirectionAuto:
+	search:
+		for _, class := range types {
+			switch class {
+			case bidi.L:
+				break search
+			case bidi.R, bidi.AL:
+				paragraph = 1
+				break search
+			}
+		}
+	}
+	sos := bidi.L
+	if paragraph == 1 {
+		sos = bidi.R
+	}
+
+	// X9: formatting characters are ignored
+	// W1: marks take the type of the previous character
+	prev := sos
+	for i, class := range types {
+		switch class {
+		case bidi.LRO, bidi.RLO, bidi.LRE, bidi.RLE, bidi.PDF, bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI, bidi.Control:
+			types[i] = bidi.BN
+			continue
+		case bidi.BN:
+			continue
+		case bidi.NSM:
+			types[i] = prev
+		}
+		prev = types[i]
+	}
+
+	// W2: European numbers after Arabic letters are Arabic numbers
+	// W3: Arabic letters are right to left
+	strong := sos
+	for i, class := range types {
+		switch class {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = class
+		case bidi.EN:
+			if strong == bidi.AL {
+				types[i] = bidi.AN
+			}
+		}
+		if class == bidi.AL {
+			types[i] = bidi.R
+		}
+	}
+
+	// W4: single separators between two numbers of the same type take their type
+	for i := 1; i+1 < len(types); i++ {
+		before, after := types[i-1], types[i+1]
+		switch {
+		case types[i] == bidi.ES && before == bidi.EN && after == bidi.EN:
+			types[i] = bidi.EN
+		case types[i] == bidi.CS && before == after && (before == bidi.EN || before == bidi.AN):
+			types[i] = before
+		}
+	}
+
+	// W5: terminators next to European numbers become numbers
+	for i := 0; i < len(types); i++ {
+		if types[i] != bidi.ET {
+			continue
+		}
+		end := i
+		for end < len(types) && (types[end] == bidi.ET || types[end] == bidi.BN) {
+			end++
+		}
+		if (i > 0 && types[i-1] == bidi.EN) || (end < len(types) && types[end] == bidi.EN) {
+			for j := i; j < end; j++ {
+				types[j] = bidi.EN
+			}
+		}
+		i = end - 1
+	}
+
+	// W6: other separators and terminators are neutral
+	// W7: European numbers after left to right text are left to right
+	strong = sos
+	for i, class := range types {
+		switch class {
+		case bidi.ES, bidi.ET, bidi.CS:
+			types[i] = bidi.ON
+		case bidi.L, bidi.R:
+			strong = class
+		case bidi.EN:
+			if strong == bidi.L {
+				types[i] = bidi.L
+			}
+		}
+	}
+
+	// N1, N2: neutrals take the direction of the surrounding text if it agrees, otherwise
+	// the paragraph direction (numbers count as right to left)
+	strongType := func(class bidi.Class) (bidi.Class, bool) {
+		switch class {
+		case bidi.L:
+			return bidi.L, true
+		case bidi.R, bidi.EN, bidi.AN:
+			return bidi.R, true
+		}
+		return class, false
+	}
+	for i := 0; i < len(types); i++ {
+		if _, ok := strongType(types[i]); ok {
+			continue
+		}
+		end := i
+		for end < len(types) {
+			if _, ok := strongType(types[end]); ok {
+				break
+			}
+			end++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before, _ = strongType(types[i-1])
+		}
+		if end < len(types) {
+			after, _ = strongType(types[end])
+		}
+		resolved := sos
+		if before == after {
+			resolved = before
+		}
+		for j := i; j < end; j++ {
+			types[j] = resolved
+		}
+		i = end - 1
+	}
+
+	// I1, I2: get levels from resolved types
+	levels := make([]int, len(types))
+	for i, class := range types {
+		level := paragraph
+		switch {
+		case paragraph%2 == 0 && class == bidi.R:
+			level++
+		case paragraph%2 == 0 && (class == bidi.AN || class == bidi.EN):
+			level += 2
+		case paragraph%2 == 1 && (class == bidi.L || class == bidi.EN || class == bidi.AN):
+			level++
+		}
+		levels[i] = level
+	}
+
+	return levels, paragraph
+}
+
+// visualOrder returns the order in which the characters of a line are displayed, given
+// their levels (L1, L2). Clusters are kept together, in logical order.
+func visualOrder(runes []shapedRune, levels []int, paragraph int) []int {
+	levels = append([]int(nil), levels...)
+
+	// L1: trailing whitespace goes back to the paragraph level
+	for i := len(runes) - 1; i >= 0; i-- {
+		props, _ := bidi.LookupRune(runes[i].Rune)
+		if class := props.Class(); class != bidi.WS && class != bidi.S && class != bidi.BN {
+			break
+		}
+		levels[i] = paragraph
+	}
+
+	// Group characters by cluster
+	var clusters [][2]int
+	for i := range runes {
+		if i > 0 && runes[i].Cluster == runes[i-1].Cluster {
+			clusters[len(clusters)-1][1] = i + 1
+			continue
+		}
+		clusters = append(clusters, [2]int{i, i + 1})
+	}
+
+	// L2: reverse every sequence at or above each level, from the highest to the lowest odd one
+	highest, lowestOdd := 0, 1<<30
+	for _, level := range levels {
+		if level > highest {
+			highest = level
+		}
+		if level%2 == 1 && level < lowestOdd {
+			lowestOdd = level
+		}
+	}
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(clusters); i++ {
+			if levels[clusters[i][0]] < level {
+				continue
+			}
+			end := i
+			for end < len(clusters) && levels[clusters[end][0]] >= level {
+				end++
+			}
+			for a, b := i, end-1; a < b; a, b = a+1, b-1 {
+				clusters[a], clusters[b] = clusters[b], clusters[a]
+			}
+			i = end
+		}
+	}
+
+	order := make([]int, 0, len(runes))
+	for _, cluster := range clusters {
+		for i := cluster[0]; i < cluster[1]; i++ {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// mirrors are the characters that are drawn mirrored in right to left text
+var mirrors = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<',
+	'«': '»', '»': '«', '‹': '›', '›': '‹', '≤': '≥', '≥': '≤',
+}
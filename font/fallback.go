@@ -0,0 +1,46 @@
+package font
+
+import (
+	"errors"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+// FallbackSeparator separates font names in a fallback chain, eg. "Title,NotoSansCJK,Symbola"
+const FallbackSeparator = ","
+
+// ErrEmptyFontChain means a font chain was created without any font in it
+var ErrEmptyFontChain = errors.New("font chain must contain at least one font")
+
+// fontFace is one of the fonts in a fallback chain
+type fontFace struct {
+	ttf  *truetype.Font
+	face font.Face
+}
+
+// faceFor returns the index of the first font in the chain that has a glyph for a
+// character, or the primary font if none does (so it draws its "missing glyph" box)
+func (f *Font) faceFor(chr rune) int {
+	for index, face := range f.faces {
+		if face.ttf.Index(chr) != 0 {
+			return index
+		}
+	}
+	return 0
+}
+
+// HasGlyph returns whether any font in the chain has a glyph for a character
+func (f *Font) HasGlyph(chr rune) bool {
+	// Baked fonts only have what's in the atlas
+	if len(f.faces) < 1 {
+		_, ok := f.Atlas[chr]
+		return ok
+	}
+	for _, face := range f.faces {
+		if face.ttf.Index(chr) != 0 {
+			return true
+		}
+	}
+	return false
+}
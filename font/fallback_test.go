@@ -0,0 +1,32 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestFontChain(t *testing.T) {
+	if _, err := MakeFontChain(nil, DefaultTextureFontSize); err != ErrEmptyFontChain {
+		t.Fatalf("expected ErrEmptyFontChain, got %v", err)
+	}
+
+	mono, _ := truetype.Parse(gomono.TTF)
+	regular, _ := truetype.Parse(goregular.TTF)
+	fnt, err := MakeFontChain([]*truetype.Font{mono, regular}, DefaultTextureFontSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if face := fnt.Atlas['a'].Face; face != 0 {
+		t.Fatalf("expected 'a' to come from the primary font, got font #%d", face)
+	}
+	if fnt.HasGlyph('日') {
+		t.Fatal("expected no font in the chain to have '日'")
+	}
+	if face := fnt.faceFor('日'); face != 0 {
+		t.Fatalf("expected missing glyphs to use the primary font, got font #%d", face)
+	}
+}
@@ -1,11 +1,9 @@
 package font
 
 import (
-	"errors"
 	"image"
+	"image/draw"
 
-	"github.com/adinfinit/texpack/maxrect"
-	"github.com/adinfinit/texpack/pack"
 	"github.com/adinfinit/texpack/sdf"
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
@@ -21,62 +19,142 @@ const SDFRadius = 8
 // DefaultTextureFontSize is the default size used for SDF texture generation
 const DefaultTextureFontSize = 64
 
-var (
-	// ErrFontDoesntFit means the font glyphs don't fit within the maximum texture limit
-	ErrFontDoesntFit = errors.New("font texture doesn't fit within texture size limits")
-)
-
-// Atlas is a dictionary that maps each font glyph to its position on a texture atlas
-type Atlas map[rune]image.Rectangle
+// glyphPadding is the space around each glyph in the atlas, used by the distance field
+const glyphPadding = SDFRadius / 2
 
-// MakeFont creates an SDF font atlas from a ttf font
+// MakeFont creates an SDF font atlas from a ttf font. Only Latin-1 is rasterized
+// upfront, every other glyph is added to the atlas the first time it's laid out.
 func MakeFont(fnt *truetype.Font, fontSize int) (*Font, error) {
-	// Create new typeface with specified size
-	face := truetype.NewFace(fnt, &truetype.Options{
-		Size:    float64(fontSize),
-		Hinting: font.HintingNone,
-	})
-
-	// Create textpack font struct
-	font := &pack.Font{
-		Font:      fnt,
-		Face:      face,
-		MaxBounds: fnt.Bounds(fixed.I(fontSize)),
-		Glyphs:    make(map[rune]*pack.Glyph, 256),
-		Padding:   SDFRadius / 2,
-		Kern:      make(map[[2]rune]fixed.Int26_6, 256),
+	return MakeFontChain([]*truetype.Font{fnt}, fontSize)
+}
+
+// MakeFontChain creates an SDF font atlas from a list of ttf fonts, the first one is the
+// primary font and the others are used, in order, for glyphs the previous ones don't have
+func MakeFontChain(fonts []*truetype.Font, fontSize int) (*Font, error) {
+	if len(fonts) < 1 {
+		return nil, ErrEmptyFontChain
 	}
 
-	// Include extra characters
-	//TODO Should include more stuff, maybe user-defined?
-	font.IncludeExtendedAscii()
+	f := &Font{
+		Pages: []*Page{makePage()},
+		Atlas: make(Atlas),
+		Size:  fontSize,
+		TTF:   fonts[0],
+	}
+	f.setFaces(fonts)
+	f.Texture = f.Pages[0].Image
+
+	// Vertical metrics come from the primary font
+	metrics := f.faces[0].face.Metrics()
+	f.ascent = fixedToFloat(metrics.Ascent)
+	f.lineHeight = fixedToFloat(metrics.Height)
+
+	// Include extended ASCII right away, since it's what most text uses
+	for chr := rune(32); chr < 256; chr++ {
+		if chr < 127 || chr >= 160 {
+			f.glyph(chr)
+		}
+	}
 
-	// Get boxes for all characters
-	boxes := font.Boxes()
+	return f, nil
+}
 
-	// Get best texture size for all boxes
-	size, ok := pack.PlaceBoxes(image.Point{MaxTextureSize, MaxTextureSize}, maxrect.Automatic, boxes)
-	if !ok {
-		return nil, ErrFontDoesntFit
+// setFaces creates the typefaces used for rasterizing glyphs
+func (f *Font) setFaces(fonts []*truetype.Font) {
+	f.faces = nil
+	for _, ttf := range fonts {
+		f.faces = append(f.faces, fontFace{
+			ttf: ttf,
+			// Create new typeface with specified size
+			face: truetype.NewFace(ttf, &truetype.Options{
+				Size:    float64(f.Size),
+				Hinting: font.HintingNone,
+			}),
+		})
+	}
+}
+
+// Include rasterizes glyphs ahead of time, useful for baking fonts (see SaveBaked)
+func (f *Font) Include(chars ...rune) {
+	for _, chr := range chars {
+		f.glyph(chr)
+	}
+}
+
+// IncludeRange rasterizes all glyphs between two characters (inclusive)
+func (f *Font) IncludeRange(from, to rune) {
+	for chr := from; chr <= to; chr++ {
+		f.glyph(chr)
 	}
+}
 
-	// Make output image for font map and draw all glyphs
-	dst := image.NewRGBA(image.Rectangle{image.ZP, size})
-	font.Draw(dst)
+// glyph returns where a character is in the atlas, rasterizing it if it's not there yet
+func (f *Font) glyph(chr rune) AtlasGlyph {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if glyph, ok := f.Atlas[chr]; ok {
+		return glyph
+	}
 
-	// Use signed distance fields on the texture
-	sdf.ApplyRGBA_Alpha(dst, SDFRadius)
+	// Baked fonts without their TTF can't add new glyphs
+	if len(f.faces) < 1 {
+		return AtlasGlyph{}
+	}
+
+	glyph := f.rasterize(chr)
+	f.Atlas[chr] = glyph
+	return glyph
+}
 
-	// Generate fontmap
-	fontmap := make(Atlas)
-	for r, box := range font.Glyphs {
-		fontmap[r] = box.Place
+// rasterize draws a glyph with its distance field on the last atlas page, starting a new
+// page when the last one is full
+func (f *Font) rasterize(chr rune) AtlasGlyph {
+	faceIndex := f.faceFor(chr)
+	face := f.faces[faceIndex].face
+
+	// Glyphs with no pixels (like spaces) only need metrics
+	bounds, advance, ok := face.GlyphBounds(chr)
+	empty := AtlasGlyph{Face: faceIndex, Advance: fixedToFloat(advance)}
+	min := image.Pt(bounds.Min.X.Floor(), bounds.Min.Y.Floor())
+	max := image.Pt(bounds.Max.X.Ceil(), bounds.Max.Y.Ceil())
+	if !ok || min.X >= max.X || min.Y >= max.Y {
+		return empty
 	}
 
-	return &Font{
-		Texture: dst,
-		Atlas:   fontmap,
-		Size:    fontSize,
-		TTF:     fnt,
-	}, nil
+	// Draw glyph on its own image, with enough padding around for the distance field
+	padding := image.Pt(glyphPadding, glyphPadding)
+	size := max.Sub(min).Add(padding.Mul(2))
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	dot := fixed.P(glyphPadding-min.X, glyphPadding-min.Y)
+	if dr, mask, maskp, _, ok := face.Glyph(dot, chr); ok {
+		draw.DrawMask(img, dr, image.White, image.ZP, mask, maskp, draw.Over)
+	}
+	sdf.ApplyRGBA_Alpha(img, SDFRadius)
+
+	// Find a place for it
+	index := len(f.Pages) - 1
+	pos, ok := f.Pages[index].place(size)
+	if !ok {
+		f.Pages = append(f.Pages, makePage())
+		index++
+		if pos, ok = f.Pages[index].place(size); !ok {
+			// Bigger than a whole page, nothing we can do
+			return empty
+		}
+	}
+
+	page := f.Pages[index]
+	place := image.Rectangle{Min: pos, Max: pos.Add(size)}
+	draw.Draw(page.Image, place, img, image.ZP, draw.Src)
+	page.Version++
+	f.Texture = f.Pages[0].Image
+
+	return AtlasGlyph{
+		Face:    faceIndex,
+		Page:    index,
+		Bounds:  place,
+		Offset:  min.Sub(padding),
+		Advance: empty.Advance,
+	}
 }
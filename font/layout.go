@@ -0,0 +1,490 @@
+package font
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// Rect is a rectangle with floating point coordinates, in pixels
+type Rect struct {
+	X, Y, Width, Height float32
+}
+
+// Glyph is a single character placed by Layout
+type Glyph struct {
+	Rune rune
+	// Index is the byte offset of the character in the laid out string
+	Index int
+	// Cluster is the Index of the first character of the glyph's cluster (a base character
+	// and the marks attached to it), glyphs in the same cluster are never split
+	Cluster int
+	// RTL is set for glyphs in right to left runs
+	RTL bool
+	// Span is the index of the span the glyph belongs to (see LayoutSpans)
+	Span int
+
+	// Bounds is where the glyph quad goes, relative to the top-left corner of the text
+	// (Y grows downwards), in texture pixels (see Font.Size)
+	Bounds Rect
+
+	// Page is the index of the atlas page the glyph is on (see Font.Pages)
+	Page int
+	// Atlas is the glyph position inside the atlas page
+	Atlas Rect
+
+	// Origin is the pen position (on the baseline) the glyph is drawn from
+	Origin float32
+	// Advance is how much the pen moves after drawing the glyph
+	Advance float32
+}
+
+// Align is how lines are aligned inside a paragraph
+type Align int
+
+// Paragraph alignments
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+	// AlignJustify stretches the spaces of every line except a paragraph's last one so
+	// that they all fill the same width
+	AlignJustify
+)
+
+// LayoutOptions controls how text is broken into lines and placed
+type LayoutOptions struct {
+	// MaxWidth is the width lines are wrapped at, in texture pixels. Zero means lines are
+	// only broken at explicit newlines.
+	MaxWidth float32
+	// LineHeight is a multiplier for the font's default line height, zero means 1
+	LineHeight float32
+	// Align is how lines are aligned relative to each other
+	Align Align
+	// Direction is the base direction of each paragraph, see DirectionAuto
+	Direction Direction
+}
+
+// Line is a single line of laid out text
+type Line struct {
+	// Start and End are the range of glyphs (in TextLayout.Glyphs) in the line
+	Start, End int
+	// Y is where the line starts from the top, Baseline is where the glyphs sit
+	Y, Baseline float32
+	// Height is the distance from this line to the next one
+	Height float32
+	// Width is the line width, not counting trailing spaces
+	Width float32
+	// TextStart and TextEnd are the byte range of the text in the line, not counting the
+	// newline that ends it
+	TextStart, TextEnd int
+}
+
+// TextLayout is a block of text broken into lines, with every glyph placed
+type TextLayout struct {
+	Text   string
+	Glyphs []Glyph
+	Lines  []Line
+	// Spans are the styled pieces of text, with Font and Scale always set
+	Spans []Span
+	// Decorations are the underlines and strikethroughs
+	Decorations []Decoration
+
+	// Width and Height are the size of the whole block, in texture pixels
+	Width, Height float32
+	// LineHeight is the distance between two lines of text with no spans
+	LineHeight float32
+}
+
+// Layout breaks text into lines (at newlines and, if requested, to fit a maximum width)
+// and places every character using the font's kerning and advance metrics. All measures
+// are in texture pixels, scale them by wantedSize/Font.Size to get the size on screen.
+func (f *Font) Layout(text string, options LayoutOptions) *TextLayout {
+	return f.LayoutSpans([]Span{{Text: text}}, options)
+}
+
+// LayoutSpans works like Layout for text made of spans with different fonts, sizes and
+// styles. Measures are in texture pixels of f, like for Layout.
+func (f *Font) LayoutSpans(spans []Span, options LayoutOptions) *TextLayout {
+	multiplier := options.LineHeight
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+
+	list, text := makeSpanList(f, spans)
+	layout := &TextLayout{
+		Text:       text,
+		Glyphs:     make([]Glyph, 0, len(text)),
+		Spans:      list.spans,
+		LineHeight: f.LineHeight() * multiplier,
+	}
+
+	// Lay out each paragraph on its own line, then wrap it
+	offset := 0
+	y := float32(0)
+	var paragraphEnds []int
+	for _, paragraph := range strings.Split(text, "\n") {
+		runes := shape(paragraph, offset, list)
+		levels, level := resolveLevels(runes, options.Direction)
+		glyphs := list.place(runes)
+		for _, span := range wrapLine(glyphs, options.MaxWidth) {
+			line := glyphs[span[0]:span[1]]
+			if needsReorder(levels[span[0]:span[1]]) {
+				line = list.placeVisual(runes[span[0]:span[1]], levels[span[0]:span[1]], level)
+			}
+
+			// Move line to the start and down to where it belongs
+			shift := float32(0)
+			if len(line) > 0 {
+				shift = line[0].Origin
+			}
+			if level%2 == 1 {
+				// Trailing spaces of right to left lines end up on the left, skip them
+				for _, glyph := range line {
+					if !unicode.IsSpace(glyph.Rune) {
+						shift = glyph.Origin
+						break
+					}
+				}
+			}
+
+			// The tallest span decides where the baseline is and how tall the line is
+			ascent, height := list.ascent(list.at(offset)), list.lineHeight(list.at(offset))
+			if len(line) > 0 {
+				ascent, height = 0, 0
+				for _, glyph := range line {
+					if spanAscent := list.ascent(glyph.Span); spanAscent > ascent {
+						ascent = spanAscent
+					}
+					if spanHeight := list.lineHeight(glyph.Span); spanHeight > height {
+						height = spanHeight
+					}
+				}
+			}
+			height *= multiplier
+
+			// Wrapping never splits clusters, so the text range starts at a cluster
+			textStart, textEnd := offset+len(paragraph), offset+len(paragraph)
+			if span[0] < len(runes) {
+				textStart = runes[span[0]].Cluster
+			}
+			if span[1] < len(runes) {
+				textEnd = runes[span[1]].Cluster
+			}
+
+			start := len(layout.Glyphs)
+			for _, glyph := range line {
+				glyph.Origin -= shift
+				glyph.Bounds.X -= shift
+				glyph.Bounds.Y += y + ascent - list.ascent(glyph.Span)
+				layout.Glyphs = append(layout.Glyphs, glyph)
+			}
+			layout.Lines = append(layout.Lines, Line{
+				Start:    start,
+				End:      len(layout.Glyphs),
+				Y:        y,
+				Baseline: y + ascent,
+				Height:   height,
+				Width:    trimmedWidth(line) - shift,
+
+				TextStart: textStart,
+				TextEnd:   textEnd,
+			})
+			y += height
+		}
+		paragraphEnds = append(paragraphEnds, len(layout.Lines)-1)
+		offset += len(paragraph) + 1
+	}
+
+	// Get block size
+	for _, line := range layout.Lines {
+		if line.Width > layout.Width {
+			layout.Width = line.Width
+		}
+	}
+	if options.MaxWidth > 0 && options.Align != AlignLeft {
+		layout.Width = options.MaxWidth
+	}
+	layout.Height = y
+
+	layout.align(options.Align, paragraphEnds)
+	layout.decorate(list)
+	return layout
+}
+
+// decorate adds underlines and strikethroughs for consecutive glyphs of decorated spans
+func (l *TextLayout) decorate(list spanList) {
+	for _, line := range l.Lines {
+		for i := line.Start; i < line.End; {
+			span := l.Glyphs[i].Span
+			end := i
+			for end < line.End && l.Glyphs[end].Span == span {
+				end++
+			}
+
+			style := l.Spans[span]
+			if style.Underline || style.Strikethrough {
+				from, to := l.Glyphs[i].Origin, l.Glyphs[i].Origin
+				for _, glyph := range l.Glyphs[i:end] {
+					if glyph.Origin < from {
+						from = glyph.Origin
+					}
+					if glyph.Origin+glyph.Advance > to {
+						to = glyph.Origin + glyph.Advance
+					}
+				}
+				thickness := list.lineHeight(span) / 16
+				if style.Underline {
+					l.Decorations = append(l.Decorations, Decoration{span, Rect{
+						X: from, Y: line.Baseline + thickness, Width: to - from, Height: thickness,
+					}})
+				}
+				if style.Strikethrough {
+					l.Decorations = append(l.Decorations, Decoration{span, Rect{
+						X: from, Y: line.Baseline - list.ascent(span)*0.3, Width: to - from, Height: thickness,
+					}})
+				}
+			}
+			i = end
+		}
+	}
+}
+
+// align moves each line (or its spaces, when justifying) according to alignment
+func (l *TextLayout) align(align Align, paragraphEnds []int) {
+	if align == AlignLeft {
+		return
+	}
+
+	isParagraphEnd := make(map[int]bool)
+	for _, end := range paragraphEnds {
+		isParagraphEnd[end] = true
+	}
+
+	for i, line := range l.Lines {
+		free := l.Width - line.Width
+		if free <= 0 {
+			continue
+		}
+		glyphs := l.Glyphs[line.Start:line.End]
+
+		switch align {
+		case AlignCenter:
+			shiftGlyphs(glyphs, free/2)
+		case AlignRight:
+			shiftGlyphs(glyphs, free)
+		case AlignJustify:
+			if isParagraphEnd[i] {
+				continue
+			}
+			// Count spaces between words (trailing ones don't count)
+			last := len(glyphs) - 1
+			for last >= 0 && unicode.IsSpace(glyphs[last].Rune) {
+				last--
+			}
+			spaces := 0
+			for _, glyph := range glyphs[:last+1] {
+				if unicode.IsSpace(glyph.Rune) {
+					spaces++
+				}
+			}
+			if spaces == 0 {
+				continue
+			}
+			extra := free / float32(spaces)
+			shift := float32(0)
+			for j := range glyphs {
+				glyphs[j].Origin += shift
+				glyphs[j].Bounds.X += shift
+				if j <= last && unicode.IsSpace(glyphs[j].Rune) {
+					glyphs[j].Advance += extra
+					shift += extra
+				}
+			}
+			l.Lines[i].Width = l.Width
+		}
+	}
+}
+
+func shiftGlyphs(glyphs []Glyph, amount float32) {
+	for i := range glyphs {
+		glyphs[i].Origin += amount
+		glyphs[i].Bounds.X += amount
+	}
+}
+
+// wrapLine breaks a line of glyphs so that each piece fits within maxWidth, preferring to
+// break after spaces and only breaking words that can't fit on a line by themselves.
+// It returns the start and end index of each piece.
+func wrapLine(glyphs []Glyph, maxWidth float32) [][2]int {
+	if maxWidth <= 0 || len(glyphs) == 0 {
+		return [][2]int{{0, len(glyphs)}}
+	}
+
+	var lines [][2]int
+	start, lastBreak := 0, -1
+	for i := 0; i < len(glyphs); i++ {
+		glyph := glyphs[i]
+		if unicode.IsSpace(glyph.Rune) {
+			// Spaces can hang past the end of the line
+			lastBreak = i
+			continue
+		}
+
+		if glyph.Origin+glyph.Advance-glyphs[start].Origin <= maxWidth || i == start {
+			continue
+		}
+
+		// Doesn't fit, break at the last space if there is one, or at the start of the
+		// current cluster otherwise
+		end := i
+		for end > start && glyphs[end].Cluster == glyphs[end-1].Cluster {
+			end--
+		}
+		if lastBreak >= start {
+			end = lastBreak + 1
+		} else if end == start {
+			// The whole line is a single cluster
+			continue
+		}
+		lines = append(lines, [2]int{start, end})
+		start, lastBreak = end, -1
+		i = end - 1
+	}
+	return append(lines, [2]int{start, len(glyphs)})
+}
+
+// trimmedWidth returns how wide a line is without counting trailing spaces
+func trimmedWidth(glyphs []Glyph) float32 {
+	last := len(glyphs) - 1
+	for last >= 0 && unicode.IsSpace(glyphs[last].Rune) {
+		last--
+	}
+	return Width(glyphs[:last+1])
+}
+
+// needsReorder returns whether a line has any right to left text
+func needsReorder(levels []int) bool {
+	for _, level := range levels {
+		if level > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// placeVisual places the characters of a line in the order they are displayed
+func (s spanList) placeVisual(runes []shapedRune, levels []int, paragraph int) []Glyph {
+	order := visualOrder(runes, levels, paragraph)
+	visual := make([]shapedRune, len(order))
+	for i, index := range order {
+		visual[i] = runes[index]
+		if levels[index]%2 == 1 {
+			if mirror, ok := mirrors[visual[i].Rune]; ok {
+				visual[i].Rune = mirror
+			}
+		}
+	}
+
+	glyphs := s.place(visual)
+	for i, index := range order {
+		glyphs[i].RTL = levels[index]%2 == 1
+	}
+	return glyphs
+}
+
+// place puts shaped characters one after the other on a single line, with the baseline
+// at the span ascent
+func (s spanList) place(runes []shapedRune) []Glyph {
+	glyphs := make([]Glyph, 0, len(runes))
+
+	var prev rune
+	var prevAtlas AtlasGlyph
+	prevSpan, base := -1, -1
+
+	curx := float32(0)
+	for _, chr := range runes {
+		// Find which font in the chain has the glyph (rasterizing it if needed)
+		fnt, scale := s.font(chr.Span), s.scale(chr.Span)
+		atlas := fnt.glyph(chr.Rune)
+		pen := curx
+		advanceWidth := atlas.Advance * scale
+
+		if chr.Mark && base >= 0 {
+			// Marks go over their base: zero-width ones already expect the pen to be after
+			// it, others are centered on it
+			if advanceWidth > 0 {
+				baseGlyph := glyphs[base]
+				pen = baseGlyph.Origin + (baseGlyph.Advance-advanceWidth)/2
+				advanceWidth = 0
+			}
+		} else {
+			// Increase space by whatever kerning is (only within the same span)
+			if base >= 0 && prevSpan == chr.Span {
+				curx += fnt.kern(prev, chr.Rune, prevAtlas, atlas) * scale
+				pen = curx
+			}
+			prev, prevAtlas, prevSpan = chr.Rune, atlas, chr.Span
+			base = len(glyphs)
+		}
+
+		// Place quad where the rasterized glyph goes
+		size := atlas.Bounds.Size()
+		glyphs = append(glyphs, Glyph{
+			Rune:    chr.Rune,
+			Index:   chr.Index,
+			Cluster: chr.Cluster,
+			Span:    chr.Span,
+			Bounds: Rect{
+				X:      pen + float32(atlas.Offset.X)*scale,
+				Y:      s.ascent(chr.Span) + float32(atlas.Offset.Y)*scale,
+				Width:  float32(size.X) * scale,
+				Height: float32(size.Y) * scale,
+			},
+			Page: atlas.Page,
+			Atlas: Rect{
+				X:      float32(atlas.Bounds.Min.X),
+				Y:      float32(atlas.Bounds.Min.Y),
+				Width:  float32(size.X),
+				Height: float32(size.Y),
+			},
+			Origin:  pen,
+			Advance: advanceWidth,
+		})
+
+		curx += advanceWidth
+	}
+
+	return glyphs
+}
+
+// Width returns how long a line of laid out glyphs is, in texture pixels
+func Width(glyphs []Glyph) float32 {
+	if len(glyphs) == 0 {
+		return 0
+	}
+	last := glyphs[len(glyphs)-1]
+	return last.Origin + last.Advance
+}
+
+// kern returns the kerning between two glyphs (only if they come from the same font)
+func (f *Font) kern(left, right rune, leftGlyph, rightGlyph AtlasGlyph) float32 {
+	if leftGlyph.Face != rightGlyph.Face {
+		return 0
+	}
+	if leftGlyph.Face < len(f.faces) {
+		ttf := f.faces[leftGlyph.Face].ttf
+		return fixedToFloat(ttf.Kern(fixed.Int26_6(f.Size<<6), ttf.Index(left), ttf.Index(right)))
+	}
+	return f.kerning[[2]rune{left, right}]
+}
+
+// LineHeight returns the default distance between two baselines, in texture pixels
+func (f *Font) LineHeight() float32 {
+	return f.lineHeight
+}
+
+func fixedToFloat(x fixed.Int26_6) float32 {
+	return float32(x) / 64
+}
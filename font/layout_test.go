@@ -0,0 +1,79 @@
+package font
+
+import "testing"
+
+// makeGlyphs places every character of a string 10 pixels apart
+func makeGlyphs(text string) []Glyph {
+	var glyphs []Glyph
+	for index, chr := range []rune(text) {
+		glyphs = append(glyphs, Glyph{Rune: chr, Index: index, Cluster: index, Origin: float32(index * 10), Advance: 10})
+	}
+	return glyphs
+}
+
+func lineStrings(glyphs []Glyph, lines [][2]int) []string {
+	var strs []string
+	for _, line := range lines {
+		str := ""
+		for _, glyph := range glyphs[line[0]:line[1]] {
+			str += string(glyph.Rune)
+		}
+		strs = append(strs, str)
+	}
+	return strs
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxWidth float32
+		expected []string
+	}{
+		{"hello world", 0, []string{"hello world"}},
+		{"hello world", 200, []string{"hello world"}},
+		{"hello world", 60, []string{"hello ", "world"}},
+		{"hello big world", 90, []string{"hello big ", "world"}},
+		{"abcdefgh", 30, []string{"abc", "def", "gh"}},
+	}
+
+	for _, test := range tests {
+		glyphs := makeGlyphs(test.text)
+		lines := lineStrings(glyphs, wrapLine(glyphs, test.maxWidth))
+		if len(lines) != len(test.expected) {
+			t.Fatalf("%q at %g: expected %q, got %q", test.text, test.maxWidth, test.expected, lines)
+		}
+		for i := range lines {
+			if lines[i] != test.expected[i] {
+				t.Fatalf("%q at %g: expected %q, got %q", test.text, test.maxWidth, test.expected, lines)
+			}
+		}
+	}
+}
+
+func TestTrimmedWidth(t *testing.T) {
+	if width := trimmedWidth(makeGlyphs("ab  ")); width != 20 {
+		t.Fatalf("expected trailing spaces to be ignored, got width %g", width)
+	}
+}
+
+func TestLayoutSpans(t *testing.T) {
+	fnt := DefaultFont()
+	layout := fnt.LayoutSpans([]Span{
+		{Text: "plain "},
+		{Text: "big", Scale: 2, Underline: true},
+	}, LayoutOptions{})
+
+	if layout.Text != "plain big" || len(layout.Spans) != 2 {
+		t.Fatalf("unexpected text %q with %d spans", layout.Text, len(layout.Spans))
+	}
+	last := layout.Glyphs[len(layout.Glyphs)-1]
+	if last.Span != 1 || last.Advance <= layout.Glyphs[0].Advance {
+		t.Fatalf("expected last glyph to be in the scaled span, got %+v", last)
+	}
+	if len(layout.Decorations) != 1 || layout.Decorations[0].Span != 1 {
+		t.Fatalf("expected a single underline for the second span, got %+v", layout.Decorations)
+	}
+	if layout.Lines[0].Height <= fnt.LineHeight() {
+		t.Fatal("expected line to grow to fit the scaled span")
+	}
+}
@@ -3,6 +3,8 @@ package font
 import (
 	"errors"
 	"image"
+	"strings"
+	"sync"
 
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font/gofont/goregular"
@@ -12,10 +14,25 @@ import (
 
 // Font holds the necessary data for using a font in youi
 type Font struct {
+	// Pages are the SDF textures holding the rasterized glyphs, new glyphs can be added
+	// to them (and new pages appended) when laying out text
+	Pages []*Page
+	Atlas Atlas
+	Size  int
+	// TTF is the primary font, see MakeFontChain
+	TTF *truetype.Font
+
+	// Texture is the image of the first page, which held every glyph before fonts had
+	// more than one page. It's kept up to date as the page grows.
+	//
+	// Deprecated: glyphs can be on any of Pages, and are only added to them when laid out.
 	Texture *image.RGBA
-	Atlas   Atlas
-	Size    int
-	TTF     *truetype.Font
+
+	faces      []fontFace
+	kerning    map[[2]rune]float32
+	ascent     float32
+	lineHeight float32
+	mutex      sync.Mutex
 }
 
 // ErrFontNotFound means any valid font formats (ttf, sdf+atlas) could not be found
@@ -29,25 +46,55 @@ func init() {
 	ClearCache()
 }
 
-// LoadFont loads either the pregenerated SDF+Atlas or TTF font from disk or binary and returns a usable youi font
+// LoadFont loads either the pregenerated SDF+Atlas or TTF font from disk or binary and returns a usable youi font.
+// fontName can be a list of names separated by FallbackSeparator, in which case glyphs missing from the
+// first font are taken from the next ones (see MakeFontChain).
 func LoadFont(fontName string) (*Font, error) {
 	// Check in local cache
 	if fnt, ok := fonts[fontName]; ok {
 		return fnt, nil
 	}
 
-	// Check for TTF file
-	ttffile, err := loader.Bytes("fonts/" + fontName + ".ttf")
+	// Prefer baked fonts, since they don't need to be generated
+	fnt, err := LoadBakedFont(fontName)
 	if err == nil {
-		// Parse truetype font
-		ttf, err := truetype.Parse(ttffile)
+		fonts[fontName] = fnt
+		return fnt, nil
+	} else if err != loader.ErrNotFound {
+		return nil, err
+	}
+
+	// Load all fonts in the chain
+	var ttfs []*truetype.Font
+	for _, name := range strings.Split(fontName, FallbackSeparator) {
+		ttf, err := loadTTF(strings.TrimSpace(name))
 		if err != nil {
 			return nil, err
 		}
+		ttfs = append(ttfs, ttf)
+	}
 
-		// Generate SDF texture
-		fonts[fontName], err = MakeFont(ttf, DefaultTextureFontSize)
-		return fonts[fontName], err
+	// Generate SDF texture
+	fnt, err = MakeFontChain(ttfs, DefaultTextureFontSize)
+	if err != nil {
+		return nil, err
+	}
+	fonts[fontName] = fnt
+	return fnt, nil
+}
+
+// loadTTF loads and parses a single TTF font, the font named "default" is Go Regular
+// unless a different one is bundled
+func loadTTF(fontName string) (*truetype.Font, error) {
+	// Check for TTF file
+	ttffile, err := loader.Bytes("fonts/" + fontName + ".ttf")
+	if err == nil {
+		// Parse truetype font
+		return truetype.Parse(ttffile)
+	}
+
+	if fontName == "default" {
+		return truetype.Parse(goregular.TTF)
 	}
 
 	return nil, ErrFontNotFound
@@ -55,22 +102,12 @@ func LoadFont(fontName string) (*Font, error) {
 
 // DefaultFont loads (and generate, if necessary) the default font for youi (goregular)
 func DefaultFont() *Font {
-	// Check if it's bundled or cached
 	fnt, err := LoadFont("default")
-	if err == nil {
-		return fnt
-	}
-
-	// Nothing? Guess we have to generate it
-	ttf, _ := truetype.Parse(goregular.TTF)
-
-	fonts["default"], err = MakeFont(ttf, DefaultTextureFontSize)
 	if err != nil {
 		// This is pretty bad
 		panic(err)
 	}
-
-	return fonts["default"]
+	return fnt
 }
 
 // ClearCache removes all entries from the font cache
@@ -0,0 +1,197 @@
+package font
+
+import "sort"
+
+// Caret is where a text cursor goes, in the same units as the layout it was taken from
+type Caret struct {
+	X, Y, Height float32
+	// Line is the index of the line the caret is on
+	Line int
+}
+
+// Ascent returns how far above the baseline glyphs go, in texture pixels
+func (f *Font) Ascent() float32 {
+	return f.ascent
+}
+
+// Descent returns how far below the baseline glyphs go, in texture pixels
+func (f *Font) Descent() float32 {
+	return f.lineHeight - f.ascent
+}
+
+// Measure returns the size of a text laid out with the given options, in texture pixels
+func (f *Font) Measure(text string, options LayoutOptions) (width, height float32) {
+	layout := f.Layout(text, options)
+	return layout.Width, layout.Height
+}
+
+// Ascent returns how far above the baseline the line goes
+func (l Line) Ascent() float32 {
+	return l.Baseline - l.Y
+}
+
+// Descent returns how far below the baseline the line goes (including extra line spacing)
+func (l Line) Descent() float32 {
+	return l.Y + l.Height - l.Baseline
+}
+
+// LineBox returns the rectangle taken by the glyphs of a line, without trailing spaces
+func (l *TextLayout) LineBox(line int) Rect {
+	info := l.Lines[line]
+	box := Rect{Y: info.Y, Width: info.Width, Height: info.Height}
+	if info.End > info.Start {
+		box.X = l.Glyphs[info.Start].Origin
+		for _, glyph := range l.Glyphs[info.Start:info.End] {
+			if glyph.Origin < box.X {
+				box.X = glyph.Origin
+			}
+		}
+	}
+	return box
+}
+
+// GlyphRect returns the cell taken by a glyph: its advance across and its line's height
+// down, unlike Glyph.Bounds which is only where the glyph quad goes
+func (l *TextLayout) GlyphRect(glyph int) Rect {
+	line := l.Lines[l.LineOfGlyph(glyph)]
+	return Rect{
+		X:      l.Glyphs[glyph].Origin,
+		Y:      line.Y,
+		Width:  l.Glyphs[glyph].Advance,
+		Height: line.Height,
+	}
+}
+
+// LineOfGlyph returns the index of the line a glyph is in
+func (l *TextLayout) LineOfGlyph(glyph int) int {
+	line := sort.Search(len(l.Lines), func(i int) bool { return l.Lines[i].End > glyph })
+	if line >= len(l.Lines) {
+		return len(l.Lines) - 1
+	}
+	return line
+}
+
+// LineAt returns the index of the line containing a byte offset of the text. Offsets
+// where a wrapped line ends belong to the next line.
+func (l *TextLayout) LineAt(index int) int {
+	for i, line := range l.Lines {
+		if index < line.TextEnd || (index == line.TextEnd && (i == len(l.Lines)-1 || l.Lines[i+1].TextStart > index)) {
+			return i
+		}
+	}
+	return len(l.Lines) - 1
+}
+
+// CaretAt returns where the caret goes when it's before the character at a byte offset
+// of the text. Offsets in the middle of a cluster go before the whole cluster.
+func (l *TextLayout) CaretAt(index int) Caret {
+	if len(l.Lines) == 0 {
+		return Caret{}
+	}
+	return l.CaretOnLine(index, l.LineAt(index))
+}
+
+// CaretOnLine works like CaretAt, but always places the caret on the given line (eg. at the
+// end of a wrapped line instead of the start of the next one)
+func (l *TextLayout) CaretOnLine(index, line int) Caret {
+	stops := l.caretStops(line)
+
+	// Find the last stop that isn't after the offset
+	stop := sort.Search(len(stops), func(i int) bool { return stops[i].index > index }) - 1
+	if stop < 0 {
+		stop = 0
+	}
+	info := l.Lines[line]
+	return Caret{X: stops[stop].x, Y: info.Y, Height: info.Height, Line: line}
+}
+
+// IndexAt returns the byte offset of the text where the caret would go when clicking at a
+// point (hit testing), points outside the text go to the closest line and position
+func (l *TextLayout) IndexAt(x, y float32) int {
+	if len(l.Lines) == 0 {
+		return 0
+	}
+
+	// Find line under the point
+	line := sort.Search(len(l.Lines), func(i int) bool { return l.Lines[i].Y+l.Lines[i].Height > y })
+	if line >= len(l.Lines) {
+		line = len(l.Lines) - 1
+	}
+
+	// Find closest caret position
+	stops := l.caretStops(line)
+	closest := stops[0]
+	for _, stop := range stops[1:] {
+		if abs(stop.x-x) < abs(closest.x-x) {
+			closest = stop
+		}
+	}
+	return closest.index
+}
+
+// caretStop is a place in a line where the caret can go
+type caretStop struct {
+	index int
+	x     float32
+}
+
+// caretStops returns where the caret can go in a line, sorted by offset in the text
+func (l *TextLayout) caretStops(line int) []caretStop {
+	info := l.Lines[line]
+
+	// Get the extent of every cluster in the line
+	type extent struct {
+		left, right float32
+		rtl         bool
+	}
+	clusters := make(map[int]*extent)
+	var order []int
+	for _, glyph := range l.Glyphs[info.Start:info.End] {
+		cluster, ok := clusters[glyph.Cluster]
+		if !ok {
+			cluster = &extent{glyph.Origin, glyph.Origin + glyph.Advance, glyph.RTL}
+			clusters[glyph.Cluster] = cluster
+			order = append(order, glyph.Cluster)
+			continue
+		}
+		if glyph.Origin < cluster.left {
+			cluster.left = glyph.Origin
+		}
+		if glyph.Origin+glyph.Advance > cluster.right {
+			cluster.right = glyph.Origin + glyph.Advance
+		}
+	}
+	sort.Ints(order)
+
+	// Carets go before a cluster, which is on its right for right to left text
+	stops := make([]caretStop, 0, len(order)+1)
+	for _, index := range order {
+		cluster := clusters[index]
+		if cluster.rtl {
+			stops = append(stops, caretStop{index, cluster.right})
+		} else {
+			stops = append(stops, caretStop{index, cluster.left})
+		}
+	}
+
+	// The end of the line is after the last cluster
+	end := caretStop{index: info.TextEnd, x: l.LineBox(line).X}
+	if len(order) > 0 {
+		last := clusters[order[len(order)-1]]
+		end.x = last.right
+		if last.rtl {
+			end.x = last.left
+		}
+	}
+	if len(stops) == 0 || stops[len(stops)-1].index < end.index {
+		stops = append(stops, end)
+	}
+	return stops
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
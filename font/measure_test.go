@@ -0,0 +1,38 @@
+package font
+
+import "testing"
+
+func TestCaretAndHitTest(t *testing.T) {
+	layout := DefaultFont().Layout("ab\n\ncd", LayoutOptions{})
+	if len(layout.Lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(layout.Lines))
+	}
+	if line := layout.Lines[2]; line.TextStart != 4 || line.TextEnd != 6 {
+		t.Fatalf("unexpected text range for last line: %+v", line)
+	}
+
+	// Carets at the start, after the first character and on the empty line
+	if caret := layout.CaretAt(0); caret.X != 0 || caret.Line != 0 {
+		t.Fatalf("unexpected caret at 0: %+v", caret)
+	}
+	if caret := layout.CaretAt(1); caret.X != layout.Glyphs[1].Origin {
+		t.Fatalf("unexpected caret at 1: %+v", caret)
+	}
+	if caret := layout.CaretAt(3); caret.Line != 1 || caret.Y != layout.Lines[1].Y {
+		t.Fatalf("unexpected caret on empty line: %+v", caret)
+	}
+
+	// Hit testing goes back to the same offsets
+	for _, index := range []int{0, 1, 2, 3, 4, 5, 6} {
+		caret := layout.CaretAt(index)
+		if hit := layout.IndexAt(caret.X, caret.Y+caret.Height/2); hit != index {
+			t.Errorf("expected hit test at caret %d to return %d, got %d", index, index, hit)
+		}
+	}
+
+	// Right to left text has its first caret on the right
+	layout = DefaultFont().Layout("אב", LayoutOptions{})
+	if caret := layout.CaretAt(0); caret.X != layout.Width {
+		t.Fatalf("expected right to left caret to start at %g, got %+v", layout.Width, caret)
+	}
+}
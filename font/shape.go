@@ -0,0 +1,238 @@
+package font
+
+import "unicode"
+
+// Shaping turns characters into the glyphs that are actually drawn:
+//   - characters are grouped in clusters (a base character and the marks/signs attached
+//     to it), which are never split by line breaks or reordered by bidi
+//   - Arabic letters take their contextual form (isolated, initial, medial, final) and
+//     lam+alef become a single ligature
+//   - Indic pre-base vowel signs are moved before the consonant they belong to
+// Forms are taken from the Unicode presentation form blocks, as the font package doesn't
+// read OpenType substitution tables. When a font lacks a form the plain character is used.
+
+// shapedRune is a character ready to be placed
+type shapedRune struct {
+	Rune rune
+	// Index is the byte offset of the source character
+	Index int
+	// Cluster is the byte offset of the first character of the cluster
+	Cluster int
+	// Mark is set for non-spacing marks, which are drawn over the previous glyph
+	Mark bool
+	// Span is the index of the span the character belongs to
+	Span int
+}
+
+// shape splits text into shaped characters, offset is where text starts in the spans text
+func shape(text string, offset int, spans spanList) []shapedRune {
+	runes := make([]shapedRune, 0, len(text))
+	for index, chr := range text {
+		runes = append(runes, shapedRune{Rune: chr, Index: offset + index, Span: spans.at(offset + index)})
+	}
+
+	runes = shapeArabic(runes, spans)
+	setClusters(runes)
+	reorderPreBase(runes)
+	return runes
+}
+
+// isMark returns whether a character is a non-spacing mark
+func isMark(chr rune) bool {
+	return unicode.In(chr, unicode.Mn, unicode.Me)
+}
+
+// joinsCluster returns whether a character belongs to the same cluster as the previous one
+func joinsCluster(prev, chr rune) bool {
+	switch {
+	case unicode.In(chr, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case chr == 0x200C || chr == 0x200D || (chr >= 0xFE00 && chr <= 0xFE0F):
+		// Zero width (non-)joiners and variation selectors
+		return true
+	case viramas[prev] && unicode.IsLetter(chr):
+		// Conjunct consonants
+		return true
+	}
+	return false
+}
+
+func setClusters(runes []shapedRune) {
+	for i := range runes {
+		runes[i].Mark = isMark(runes[i].Rune)
+		if i > 0 && joinsCluster(runes[i-1].Rune, runes[i].Rune) {
+			runes[i].Cluster = runes[i-1].Cluster
+		} else {
+			runes[i].Cluster = runes[i].Index
+		}
+	}
+}
+
+// viramas are the Indic signs that join two consonants
+var viramas = map[rune]bool{
+	0x094D: true, 0x09CD: true, 0x0A4D: true, 0x0ACD: true, 0x0B4D: true,
+	0x0BCD: true, 0x0C4D: true, 0x0CCD: true, 0x0D4D: true, 0x0DCA: true,
+}
+
+// preBaseSigns are Indic vowel signs written after the consonant but drawn before it
+var preBaseSigns = map[rune]bool{
+	0x093F: true,                             // Devanagari I
+	0x094E: true,                             // Devanagari prishthamatra E
+	0x09BF: true, 0x09C7: true, 0x09C8: true, // Bengali I, E, AI
+	0x0A3F: true,                             // Gurmukhi I
+	0x0ABF: true,                             // Gujarati I
+	0x0B47: true,                             // Oriya E
+	0x0BC6: true, 0x0BC7: true, 0x0BC8: true, // Tamil E, EE, AI
+	0x0D46: true, 0x0D47: true, 0x0D48: true, // Malayalam E, EE, AI
+	0x0DD9: true, // Sinhala E
+}
+
+// reorderPreBase moves pre-base vowel signs to the start of their cluster
+func reorderPreBase(runes []shapedRune) {
+	for i := range runes {
+		if !preBaseSigns[runes[i].Rune] {
+			continue
+		}
+		start := i
+		for start > 0 && runes[start-1].Cluster == runes[i].Cluster {
+			start--
+		}
+		sign := runes[i]
+		copy(runes[start+1:i+1], runes[start:i])
+		runes[start] = sign
+	}
+}
+
+// joiningType is how an Arabic character connects to its neighbours
+type joiningType int
+
+const (
+	joinNone joiningType = iota
+	joinRight
+	joinDual
+	joinCausing
+	joinTransparent
+)
+
+// arabicLetter holds the presentation forms of a letter: isolated, final, initial, medial
+type arabicLetter struct {
+	join  joiningType
+	forms [4]rune
+}
+
+var arabicLetters = make(map[rune]arabicLetter)
+
+func init() {
+	// Letters from U+0621 to U+064A with how many forms they have in Presentation Forms-B
+	// (1: isolated only, 2: right-joining, 4: dual-joining), in order starting from U+FE80
+	counts := []struct {
+		from, to rune
+		forms    int
+	}{
+		{0x0621, 0x0621, 1}, {0x0622, 0x0625, 2}, {0x0626, 0x0626, 4}, {0x0627, 0x0627, 2},
+		{0x0628, 0x0628, 4}, {0x0629, 0x0629, 2}, {0x062A, 0x062E, 4}, {0x062F, 0x0632, 2},
+		{0x0633, 0x063A, 4}, {0x0641, 0x0647, 4}, {0x0648, 0x0648, 2}, {0x0649, 0x0649, 2},
+		{0x064A, 0x064A, 4},
+	}
+	form := rune(0xFE80)
+	for _, group := range counts {
+		for chr := group.from; chr <= group.to; chr++ {
+			letter := arabicLetter{join: joinNone}
+			switch group.forms {
+			case 2:
+				letter.join = joinRight
+			case 4:
+				letter.join = joinDual
+			}
+			for i := 0; i < group.forms; i++ {
+				letter.forms[i] = form
+				form++
+			}
+			arabicLetters[chr] = letter
+		}
+	}
+}
+
+// lamAlef maps the alef variants to their lam+alef isolated ligature (the final form is the next character)
+var lamAlef = map[rune]rune{
+	0x0622: 0xFEF5, // with madda above
+	0x0623: 0xFEF7, // with hamza above
+	0x0625: 0xFEF9, // with hamza below
+	0x0627: 0xFEFB,
+}
+
+const arabicLam = 0x0644
+
+func arabicJoining(chr rune) joiningType {
+	if letter, ok := arabicLetters[chr]; ok {
+		return letter.join
+	}
+	switch {
+	case chr == 0x0640 || chr == 0x200D:
+		// Tatweel and zero width joiner
+		return joinCausing
+	case isMark(chr):
+		return joinTransparent
+	}
+	return joinNone
+}
+
+// shapeArabic replaces Arabic letters with their contextual forms and lam+alef ligatures
+func shapeArabic(runes []shapedRune, spans spanList) []shapedRune {
+	// neighbour returns the joining type of the closest non-transparent character in a direction
+	neighbour := func(i, step int) joiningType {
+		for i += step; i >= 0 && i < len(runes); i += step {
+			if join := arabicJoining(runes[i].Rune); join != joinTransparent {
+				return join
+			}
+		}
+		return joinNone
+	}
+
+	out := make([]shapedRune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		chr := runes[i]
+		letter, ok := arabicLetters[chr.Rune]
+		if !ok {
+			out = append(out, chr)
+			continue
+		}
+
+		prev := neighbour(i, -1)
+		joinsPrev := prev == joinDual || prev == joinCausing
+
+		// Lam followed by alef becomes a single glyph
+		if chr.Rune == arabicLam && i+1 < len(runes) {
+			if ligature, ok := lamAlef[runes[i+1].Rune]; ok {
+				if joinsPrev {
+					ligature++
+				}
+				if spans.font(chr.Span).HasGlyph(ligature) {
+					chr.Rune = ligature
+					out = append(out, chr)
+					i++
+					continue
+				}
+			}
+		}
+
+		joinsPrev = joinsPrev && letter.join != joinNone
+		next := neighbour(i, 1)
+		joinsNext := letter.join == joinDual && (next == joinDual || next == joinRight || next == joinCausing)
+
+		form := letter.forms[0]
+		switch {
+		case joinsPrev && joinsNext:
+			form = letter.forms[3]
+		case joinsNext:
+			form = letter.forms[2]
+		case joinsPrev:
+			form = letter.forms[1]
+		}
+		if form != 0 && spans.font(chr.Span).HasGlyph(form) {
+			chr.Rune = form
+		}
+		out = append(out, chr)
+	}
+	return out
+}
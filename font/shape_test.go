@@ -0,0 +1,63 @@
+package font
+
+import "testing"
+
+func shapedString(runes []shapedRune) string {
+	str := ""
+	for _, chr := range runes {
+		str += string(chr.Rune)
+	}
+	return str
+}
+
+func TestShapeClusters(t *testing.T) {
+	spans, _ := makeSpanList(DefaultFont(), []Span{{}})
+
+	// Devanagari "कि": the vowel sign I is drawn before the consonant
+	runes := shape("कि", 0, spans)
+	if str := shapedString(runes); str != "िक" {
+		t.Fatalf("expected pre-base sign to be moved first, got %q", str)
+	}
+	if runes[0].Cluster != runes[1].Cluster {
+		t.Fatal("expected consonant and vowel sign to be in the same cluster")
+	}
+
+	// "é" written with a combining accent
+	runes = shape("éx", 0, spans)
+	if !runes[1].Mark || runes[1].Cluster != 0 || runes[2].Cluster != 3 {
+		t.Fatalf("unexpected clusters: %+v", runes)
+	}
+}
+
+func TestBidiOrder(t *testing.T) {
+	tests := []struct {
+		text      string
+		direction Direction
+		expected  string
+	}{
+		{"abc", DirectionAuto, "abc"},
+		{"abc אבג def", DirectionAuto, "abc גבא def"},
+		{"אבג 123", DirectionAuto, "123 גבא"},
+		{"אבג (x)", DirectionAuto, "(x) גבא"},
+		{"abc", RightToLeft, "abc"},
+	}
+
+	for _, test := range tests {
+		runes := []shapedRune{}
+		for index, chr := range test.text {
+			runes = append(runes, shapedRune{Rune: chr, Index: index, Cluster: index})
+		}
+		levels, paragraph := resolveLevels(runes, test.direction)
+		visual := ""
+		for _, index := range visualOrder(runes, levels, paragraph) {
+			chr := runes[index].Rune
+			if mirror, ok := mirrors[chr]; ok && levels[index]%2 == 1 {
+				chr = mirror
+			}
+			visual += string(chr)
+		}
+		if visual != test.expected {
+			t.Fatalf("%q: expected %q, got %q (levels %v)", test.text, test.expected, visual, levels)
+		}
+	}
+}
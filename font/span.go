@@ -0,0 +1,91 @@
+package font
+
+import (
+	"image/color"
+	"sort"
+)
+
+// Span is a piece of text with its own style, see LayoutSpans
+type Span struct {
+	Text string
+	// Font is the font used for the span, nil means the font the text is laid out with
+	Font *Font
+	// Scale is the span size relative to the size of the font the text is laid out with,
+	// zero means 1
+	Scale float32
+	// Color is the span color, nil means the text color
+	Color color.Color
+
+	// Bold and Italic are synthesized by renderers, pick a bold/italic Font for the real thing
+	Bold, Italic bool
+	// Underline and Strikethrough add lines to TextLayout.Decorations
+	Underline, Strikethrough bool
+}
+
+// Decoration is a line drawn under or over a span
+type Decoration struct {
+	// Span is the index of the span the decoration belongs to
+	Span   int
+	Bounds Rect
+}
+
+// ItalicSlant is how much italic glyphs lean to the right, relative to their height
+const ItalicSlant = 0.2
+
+// BoldWeight is how much bold glyphs are thickened, as a fraction of the distance field range
+const BoldWeight = 0.1
+
+// spanList holds the spans being laid out, with where each one starts in the whole text
+type spanList struct {
+	spans  []Span
+	starts []int
+	base   *Font
+}
+
+func makeSpanList(base *Font, spans []Span) (spanList, string) {
+	list := spanList{base: base}
+	text := ""
+	for _, span := range spans {
+		if span.Font == nil {
+			span.Font = base
+		}
+		if span.Scale <= 0 {
+			span.Scale = 1
+		}
+		list.spans = append(list.spans, span)
+		list.starts = append(list.starts, len(text))
+		text += span.Text
+	}
+	return list, text
+}
+
+// at returns the index of the span containing the character at a byte offset
+func (s spanList) at(index int) int {
+	return sort.Search(len(s.starts), func(i int) bool { return s.starts[i] > index }) - 1
+}
+
+// font returns the font of a span
+func (s spanList) font(span int) *Font {
+	if span < 0 || span >= len(s.spans) {
+		return s.base
+	}
+	return s.spans[span].Font
+}
+
+// scale returns how much the glyphs of a span have to be scaled to be in base font texture pixels
+func (s spanList) scale(span int) float32 {
+	if span < 0 || span >= len(s.spans) {
+		return 1
+	}
+	return s.spans[span].Scale * float32(s.base.Size) / float32(s.spans[span].Font.Size)
+}
+
+// ascent returns the ascent of a span, in base font texture pixels
+func (s spanList) ascent(span int) float32 {
+	return s.font(span).ascent * s.scale(span)
+}
+
+// lineHeight returns the line height of a span, in base font texture pixels
+func (s spanList) lineHeight(span int) float32 {
+	return s.font(span).lineHeight * s.scale(span)
+}
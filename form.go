@@ -2,89 +2,348 @@ package youi
 
 import (
 	"image"
+	"image/color"
 	"io"
+	"reflect"
 
 	"github.com/kataras/go-errors"
 
 	"github.com/hamcha/youi/components"
 	"github.com/hamcha/youi/components/builtin"
 	"github.com/hamcha/youi/opengl"
+	"github.com/hamcha/youi/render"
 	"github.com/hamcha/youi/yuml"
 )
 
 var (
-	ErrYUMLRootMustBePage  = errors.New("YUML root must be <Page>")
+	ErrYUMLRootMustBePage = errors.New("YUML root must be <Page>")
+	ErrNoChildSettings    = errors.New("Element \"%s\" does not accept child settings")
+	ErrTemplateChildren   = errors.New("Element \"%s\" takes exactly one child as template")
+	ErrCouldNotBind       = errors.New("Could not bind attribute \"%s\" of element \"%s\"")
+
+	// ErrCouldNotMakeElement used to wrap errors in elements.
+	//
+	// Deprecated: errors in elements are now a *yuml.Error (collected in a yuml.ErrorList),
+	// which tells which element failed and where; use errors.As to get it.
 	ErrCouldNotMakeElement = errors.New("Could not make element \"%s\"")
 )
 
 type Form struct {
 	Root *builtin.Page
 
-	window *opengl.Window
+	// FocusRingColor is the color of the ring drawn around focused components (including
+	// the ones that draw their own, see components.FocusRingDrawer), nil means no ring
+	FocusRingColor color.Color
+
+	renderer render.Renderer
+	window   *opengl.Window
+	input    inputState
+	focused  components.Component
+	handlers Handlers
+	watcher  *watcher
+
+	theme       *Theme
+	stylesheets []*Stylesheet
+
+	// source and sources are the last document loaded and its handlers, to make it again
+	source  *yuml.Element
+	sources []interface{}
 }
 
+// MakeForm creates a form that draws on an OpenGL window and receives its input
 func MakeForm(window *opengl.Window) *Form {
-	form := &Form{
-		Root:   new(builtin.Page),
-		window: window,
-	}
-	form.setRootVars()
+	form := makeForm(opengl.MakeRenderer(window))
+	form.window = window
+
+	// Copy and paste go through the system clipboard
+	components.SetClipboard(window)
 
 	// Set resize callback
 	window.SetResizeCallback(form.onResize)
 
+	// Set input callbacks
+	form.bindEvents()
+
+	return form
+}
+
+// MakeOffscreenForm creates a form that is not bound to any window, such as one drawing
+// on a software.Renderer. Offscreen forms receive no input events.
+func MakeOffscreenForm(renderer render.Renderer) *Form {
+	return makeForm(renderer)
+}
+
+func makeForm(renderer render.Renderer) *Form {
+	form := &Form{
+		Root:           new(builtin.Page),
+		FocusRingColor: components.DefaultFocusRingColor,
+		renderer:       renderer,
+		handlers:       make(Handlers),
+	}
+	form.setRootVars()
 	return form
 }
 
 func (f *Form) Draw() {
-	f.window.Clear()
-	f.Root.Draw()
-	f.window.DrawDone()
+	f.renderer.Clear()
+	f.updateFocusRing()
+	f.Root.Draw(f.renderer)
+	f.drawFocusRing()
+	f.drawReloadError()
+	f.renderer.Present()
+}
+
+// SetDataContext sets what bindings in the form (like Text="{Binding User.Name}") resolve
+// against, and updates them. It returns the first binding that couldn't be updated, if any.
+func (f *Form) SetDataContext(data interface{}) error {
+	return f.Root.SetDataContext(data)
+}
+
+// DataContext returns what bindings in the form resolve against
+func (f *Form) DataContext() interface{} {
+	return f.Root.DataContext()
 }
 
 func (f *Form) onResize(width, height int) {
 	f.Root.SetSize(image.Point{width, height})
 }
 
-func (f *Form) LoadYUML(reader io.Reader) error {
+// LoadYUML replaces the form's content with a tree described in YUML. Event attributes
+// (like OnClick="save") use the handlers given, which are either Handlers or values whose
+// methods are handlers, and then the ones added with RegisterHandler. Handlers that are
+// pointers to structs also get their tagged fields filled, see FillFields. Errors in the
+// document are returned all together as a yuml.ErrorList, with their positions.
+func (f *Form) LoadYUML(reader io.Reader, handlers ...interface{}) error {
 	// Parse code
 	yumlElem, err := yuml.ParseYUML(reader)
 	if err != nil {
 		return err
 	}
+	return f.load(yumlElem, handlers, false)
+}
+
+// AddStylesheet adds styles that documents loaded afterwards can use, after the theme's
+// and before the ones they declare
+func (f *Form) AddStylesheet(sheet *Stylesheet) {
+	f.stylesheets = append(f.stylesheets, sheet)
+}
 
+// load makes a tree out of a YUML document and replaces the form's content with it.
+// keepState is whether state (see components.StateHolder) and focus are taken from the
+// matching components of the old tree.
+func (f *Form) load(yumlElem *yuml.Element, handlers []interface{}, keepState bool) error {
 	// Create tree
-	elem, err := makeYUMLcomponentTree(yumlElem)
+	builder := &yumlBuilder{
+		handlers: append(append([]interface{}{}, handlers...), f.handlers),
+		theme:    f.theme,
+	}
+	if f.theme != nil && f.theme.Styles != nil {
+		builder.styles = append(builder.styles, f.theme.Styles)
+	}
+	builder.styles = append(builder.styles, f.stylesheets...)
+	elem, err := builder.build(yumlElem)
 	if err != nil {
 		return err
 	}
 
 	// Add to root and return
-	var ok bool
-	f.Root, ok = elem.(*builtin.Page)
+	root, ok := elem.(*builtin.Page)
 	if !ok {
-		return ErrYUMLRootMustBePage
+		return yuml.ElementError(yumlElem, ErrYUMLRootMustBePage)
 	}
+	for _, handler := range handlers {
+		if isStructPointer(reflect.ValueOf(handler)) {
+			if err := fillFields(root, handler); err != nil {
+				return err
+			}
+		}
+	}
+	var focused components.Component
+	if keepState {
+		components.CopyState(f.Root, root)
+		if oldFocused := f.Focused(); oldFocused != nil {
+			for current, old := range components.MatchComponents(f.Root, root) {
+				if old == oldFocused {
+					focused = current
+				}
+			}
+		}
+	}
+
+	// Stop the old tree from listening to changes and free what it holds on the renderer
+	data := f.Root.DataContext()
+	components.Unbind(f.Root)
+	components.Destroy(f.Root)
+	f.Root = root
+	f.source, f.sources = yumlElem, handlers
 	f.setRootVars()
+	if focused != nil {
+		f.Focus(focused)
+	}
+
+	// Keep the data context, if one was set before loading
+	if data != nil {
+		return f.Root.SetDataContext(data)
+	}
 	return nil
 }
 
-func makeYUMLcomponentTree(element *yuml.Element) (components.Component, error) {
-	elem, err := makeComponent(element.Name.Space, element.Name.Local, toAttributeList(element.Attributes))
-	if err != nil {
+// yumlBuilder makes component trees out of YUML elements, collecting all the errors it
+// finds instead of stopping at the first one
+type yumlBuilder struct {
+	// handlers are where handlers for event attributes are looked up, in order
+	handlers []interface{}
+	// styles are the stylesheets in scope, from the outermost
+	styles []*Stylesheet
+	theme  *Theme
+
+	// ids are the IDs used so far, except in templates since their copies share them
+	ids        map[string]bool
+	inTemplate bool
+
+	errors yuml.ErrorList
+}
+
+// build makes a tree out of an element, returning all the errors found in it
+func (b *yumlBuilder) build(element *yuml.Element) (components.Component, error) {
+	b.errors, b.ids = nil, make(map[string]bool)
+	elem := b.makeYUMLcomponentTree(element)
+	if err := b.errors.Err(); err != nil {
 		return nil, err
 	}
+	return elem, nil
+}
+
+// makeYUMLcomponentTree makes a component out of an element and its children. Errors are
+// added to the builder, the component is nil if it could not be made at all.
+func (b *yumlBuilder) makeYUMLcomponentTree(element *yuml.Element) components.Component {
+	// Styles declared in an element are used by it and everything inside it
+	element, declared := splitStyles(element)
+	count, err := b.pushStyles(declared)
+	defer b.popStyles(count)
+	if err != nil {
+		return b.skipElement(element, err)
+	}
+	attributes, err := b.applyStyles(element.Name.Local, toAttributeList(element.Attributes))
+	if err != nil {
+		return b.skipElement(element, err)
+	}
+
+	// Event attributes are handled here, bound attributes are set later from the data context
+	attributes, events := eventAttributes(attributes)
+	attributes, bindings, err := components.ParseBindings(attributes)
+	if err != nil {
+		return b.skipElement(element, err)
+	}
+	elem, err := makeComponent(element.Name.Space, element.Name.Local, attributes)
+	if err != nil {
+		return b.skipElement(element, err)
+	}
+
+	// Apply attributes that all components support
+	if min, max, err := components.ParseConstraints(attributes); err != nil {
+		b.errors.Add(element, err)
+	} else {
+		elem.SetConstraints(min, max)
+	}
+	if err := components.ParseFocus(elem, attributes); err != nil {
+		b.errors.Add(element, err)
+	}
+	if err := components.ParseClip(elem, attributes); err != nil {
+		b.errors.Add(element, err)
+	}
+	if err := components.ParseID(elem, attributes); err != nil {
+		b.errors.Add(element, err)
+	}
+	b.checkID(element, elem)
+	b.bindHandlers(element, elem, events)
+	for _, binding := range bindings {
+		if err := components.Bind(elem, binding); err != nil {
+			b.errors.Add(element, ErrCouldNotBind.Format(binding.Attribute, element.Name.Local).AppendErr(err))
+		}
+	}
+
+	// Text components take their content and children as styled text
+	if handler, ok := elem.(components.InlineContentHandler); ok && hasInlineContent(element) {
+		runs, err := makeTextRuns(element)
+		if err != nil {
+			b.errors.Add(element, err)
+		} else if err := handler.SetInlineContent(runs); err != nil {
+			b.errors.Add(element, err)
+		}
+		return elem
+	}
+
+	// Templated containers make copies of their child later on
+	if handler, ok := elem.(components.TemplateHandler); ok {
+		b.setTemplate(element, handler)
+		return elem
+	}
 
 	// Check for children
 	for _, child := range element.Children {
-		childelem, err := makeYUMLcomponentTree(child.Element)
-		if err != nil {
-			return nil, ErrCouldNotMakeElement.Format(element.Name.Local).AppendErr(err)
+		childelem := b.makeYUMLcomponentTree(child.Element)
+		if childelem == nil {
+			continue
 		}
 		elem.AppendChild(childelem)
+
+		// Apply parent-related settings, if any
+		if len(child.Settings) > 0 {
+			container, ok := elem.(components.ChildSettingsHandler)
+			if !ok {
+				b.errors.Add(child.Element, ErrNoChildSettings.Format(element.Name.Local))
+				continue
+			}
+			if err := container.SetChildSettings(childelem, toAttributeList(child.Settings)); err != nil {
+				b.errors.Add(child.Element, err)
+			}
+		}
 	}
 
-	return elem, nil
+	return elem
+}
+
+// skipElement adds the error that stopped an element from being made, then still goes
+// through its children so that their errors are reported too
+func (b *yumlBuilder) skipElement(element *yuml.Element, err error) components.Component {
+	b.errors.Add(element, err)
+	for _, child := range element.Children {
+		// Inline content can't be checked without knowing the component takes it
+		if isInlineElement(child.Element) {
+			continue
+		}
+		b.makeYUMLcomponentTree(child.Element)
+	}
+	return nil
+}
+
+// setTemplate gives an element's only child to a container as template, making a copy
+// right away so that errors in it come up while loading
+func (b *yumlBuilder) setTemplate(element *yuml.Element, handler components.TemplateHandler) {
+	if len(element.Children) != 1 || len(element.Children[0].Settings) > 0 {
+		b.errors.Add(element, ErrTemplateChildren.Format(element.Name.Local))
+		return
+	}
+	child := element.Children[0].Element
+	inTemplate := b.inTemplate
+	b.inTemplate = true
+	b.makeYUMLcomponentTree(child)
+	b.inTemplate = inTemplate
+
+	// Copies are made later on, with the styles in scope now
+	template := &yumlBuilder{
+		handlers:   b.handlers,
+		styles:     append([]*Stylesheet{}, b.styles...),
+		theme:      b.theme,
+		inTemplate: true,
+	}
+	err := handler.SetTemplate(func() (components.Component, error) {
+		return template.build(child)
+	})
+	if err != nil {
+		b.errors.Add(element, err)
+	}
 }
 
 func toAttributeList(y yuml.Attributes) components.AttributeList {
@@ -96,5 +355,5 @@ func toAttributeList(y yuml.Attributes) components.AttributeList {
 }
 
 func (f *Form) setRootVars() {
-	f.Root.SetSize(f.window.GetSize())
+	f.Root.SetSize(f.renderer.Size())
 }
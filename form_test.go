@@ -0,0 +1,50 @@
+package youi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hamcha/youi/yuml"
+)
+
+func TestLoadYUMLErrors(t *testing.T) {
+	const src = `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Stack Direction="Sideways">
+		<Label FontSize="-1" />
+	</Stack>
+	<Button Text="Ok" Focusable="maybe" />
+	<Missing />
+</Page>`
+
+	form := makeTestForm()
+	err := form.LoadYUML(strings.NewReader(src))
+	list, ok := err.(yuml.ErrorList)
+	if !ok {
+		t.Fatalf("Expected a yuml.ErrorList, got %v", err)
+	}
+
+	// Children of elements that could not be made are still checked
+	expected := []struct {
+		line, column int
+		path         string
+	}{
+		{2, 2, "Page/Stack"},
+		{3, 3, "Page/Stack/Label"},
+		{5, 2, "Page/Button"},
+		{6, 2, "Page/Missing"},
+	}
+	if len(list) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d:\n%s", len(expected), len(list), list.Error())
+	}
+	for i, want := range expected {
+		got := list[i]
+		if got.Position.Line != want.line || got.Position.Column != want.column || got.Path != want.path {
+			t.Errorf("Expected error %d at %d:%d in %s, got %s", i, want.line, want.column, want.path, got.Error())
+		}
+	}
+
+	// Nothing is loaded when there are errors
+	if len(form.Root.Children()) != 0 {
+		t.Errorf("Expected the form to stay empty, got %d children", len(form.Root.Children()))
+	}
+}
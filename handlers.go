@@ -0,0 +1,123 @@
+package youi
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kataras/go-errors"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/yuml"
+)
+
+// Handler errors
+var (
+	ErrUnknownHandler = errors.New("Unknown handler \"%s\" for %s")
+	ErrInvalidHandler = errors.New("Handler \"%s\" must be a func() or a func(*components.Event)")
+)
+
+// Handlers is a registry of event handlers by name, for YUML event attributes such as
+// OnClick="save". Handlers are either func() or func(*components.Event).
+type Handlers map[string]interface{}
+
+// RegisterHandler adds a handler that event attributes in YUML loaded afterwards can use
+func (f *Form) RegisterHandler(name string, handler interface{}) error {
+	if _, ok := toListener(handler); !ok {
+		return ErrInvalidHandler.Format(name)
+	}
+	f.handlers[name] = handler
+	return nil
+}
+
+// toListener wraps a handler in an event listener, if it has a supported signature
+func toListener(handler interface{}) (components.EventListener, bool) {
+	switch fn := handler.(type) {
+	case func():
+		return func(*components.Event) { fn() }, true
+	case func(*components.Event):
+		return fn, true
+	case components.EventListener:
+		return fn, true
+	}
+	return nil, false
+}
+
+// eventAttributes takes event attributes (On followed by an event name, like OnClick) out
+// of an attribute list, returning the handler names by event
+func eventAttributes(list components.AttributeList) (components.AttributeList, map[components.EventType]string) {
+	events := make(map[components.EventType]string)
+	rest := make(components.AttributeList)
+	for name, value := range list {
+		if strings.HasPrefix(name, "On") {
+			if eventType, ok := components.ParseEventType(strings.TrimPrefix(name, "On")); ok {
+				events[eventType] = value.String()
+				continue
+			}
+		}
+		rest[name] = value
+	}
+	return rest, events
+}
+
+// findHandler looks up a handler by name in handler sources: Handlers registries and any
+// other value, whose methods are the handlers. The first letter of method names is made
+// uppercase, so that OnClick="save" finds a Save method.
+func findHandler(sources []interface{}, name string) (interface{}, bool) {
+	for _, source := range sources {
+		switch source := source.(type) {
+		case nil:
+			continue
+		case Handlers:
+			if handler, ok := source[name]; ok {
+				return handler, true
+			}
+		case map[string]interface{}:
+			if handler, ok := source[name]; ok {
+				return handler, true
+			}
+		default:
+			value := reflect.ValueOf(source)
+			if method := value.MethodByName(name); method.IsValid() {
+				return method.Interface(), true
+			}
+			if method := value.MethodByName(exportedName(name)); method.IsValid() {
+				return method.Interface(), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// exportedName makes the first letter of a name uppercase
+func exportedName(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// bindHandlers adds listeners for the event attributes of a component
+func (b *yumlBuilder) bindHandlers(element *yuml.Element, component components.Component, events map[components.EventType]string) {
+	// Go through events in order, so that errors always come in the same order
+	types := make([]components.EventType, 0, len(events))
+	for eventType := range events {
+		types = append(types, eventType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	for _, eventType := range types {
+		name := events[eventType]
+		handler, ok := findHandler(b.handlers, name)
+		if !ok {
+			b.errors.Add(element, ErrUnknownHandler.Format(name, "On"+eventType.String()))
+			continue
+		}
+		listener, ok := toListener(handler)
+		if !ok {
+			b.errors.Add(element, ErrInvalidHandler.Format(name))
+			continue
+		}
+		component.AddEventListener(eventType, listener)
+	}
+}
@@ -0,0 +1,95 @@
+package youi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/software"
+	"github.com/hamcha/youi/yuml"
+)
+
+// makeTestForm creates a form that draws in memory
+func makeTestForm() *Form {
+	return MakeOffscreenForm(software.MakeRenderer(200, 100, nil))
+}
+
+// testEditor is a handler source, its methods are looked up by event attributes
+type testEditor struct {
+	saved int
+}
+
+func (e *testEditor) Save() {
+	e.saved++
+}
+
+// Open has a signature that can't be used as handler
+func (e *testEditor) Open(path string) {}
+
+// click sends a click event to the component with an Id
+func click(t *testing.T, form *Form, id string) {
+	target := form.FindByID(id)
+	if target == nil {
+		t.Fatalf("No element with Id %s", id)
+	}
+	components.DispatchEvent(form.Root, target, &components.Event{Type: components.EventClick})
+}
+
+func TestLoadYUMLHandlers(t *testing.T) {
+	const src = `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Button Id="save" Text="Save" OnClick="save" />
+	<Button Id="cancel" Text="Cancel" OnClick="cancel" />
+</Page>`
+
+	editor := &testEditor{}
+	cancelled := 0
+	handlers := Handlers{"cancel": func() { cancelled++ }}
+
+	form := makeTestForm()
+	if err := form.LoadYUML(strings.NewReader(src), handlers, editor); err != nil {
+		t.Fatalf("Could not load YUML: %s", err.Error())
+	}
+
+	// save is found as the Save method
+	click(t, form, "save")
+	click(t, form, "cancel")
+	click(t, form, "cancel")
+	if editor.saved != 1 {
+		t.Errorf("Expected Save to be called once, got %d", editor.saved)
+	}
+	if cancelled != 2 {
+		t.Errorf("Expected cancel to be called twice, got %d", cancelled)
+	}
+}
+
+func TestLoadYUMLHandlerErrors(t *testing.T) {
+	const src = `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Button Text="Open" OnClick="open" />
+	<Button Text="Quit" OnClick="quit" />
+</Page>`
+
+	form := makeTestForm()
+	err := form.LoadYUML(strings.NewReader(src), &testEditor{})
+	list, ok := err.(yuml.ErrorList)
+	if !ok || len(list) != 2 {
+		t.Fatalf("Expected two errors, got %v", err)
+	}
+
+	expected := []struct {
+		line    int
+		path    string
+		message string
+	}{
+		{2, "Page/Button", ErrInvalidHandler.Format("open").Error()},
+		{3, "Page/Button[2]", ErrUnknownHandler.Format("quit", "OnClick").Error()},
+	}
+	for i, want := range expected {
+		got := list[i]
+		if got.Position.Line != want.line || got.Position.Column != 2 || got.Path != want.path {
+			t.Errorf("Expected error %d at %d:2 in %s, got %s", i, want.line, want.path, got.Error())
+		}
+		if got.Err.Error() != want.message {
+			t.Errorf("Expected error %d to be %q, got %q", i, want.message, got.Err.Error())
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package youi
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/components/builtin"
+	"github.com/hamcha/youi/loader"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/yuml"
+)
+
+// WatchInterval is how often files passed to WatchYUML are checked for changes
+var WatchInterval = 500 * time.Millisecond
+
+// ReloadErrorBackground is the color behind errors shown by WatchYUML
+var ReloadErrorBackground = color.RGBA{0x40, 0x08, 0x08, 0xe8}
+
+// LoadYUMLFile loads a YUML file through the loader, see LoadYUML
+func (f *Form) LoadYUMLFile(path string, handlers ...interface{}) error {
+	data, err := loader.Bytes(path)
+	if err != nil {
+		return err
+	}
+	element, err := yuml.ParseYUMLFile(bytes.NewReader(data), path)
+	if err != nil {
+		return err
+	}
+	return f.load(element, handlers, false)
+}
+
+// WatchYUML loads a YUML file through the loader and loads it again every time it changes,
+// keeping the state of components where possible. It's meant for development: errors are
+// shown on top of the form (which keeps the last tree that loaded) instead of being
+// returned. Changes are only applied by Update, which must be called from the main loop.
+func (f *Form) WatchYUML(path string, handlers ...interface{}) {
+	f.StopWatching()
+	f.watcher = &watcher{
+		path:     path,
+		handlers: handlers,
+		changes:  make(chan watchResult, 1),
+		stop:     make(chan struct{}),
+	}
+	go f.watcher.run()
+}
+
+// StopWatching stops checking the file passed to WatchYUML for changes
+func (f *Form) StopWatching() {
+	if f.watcher == nil {
+		return
+	}
+	close(f.watcher.stop)
+	f.watcher = nil
+}
+
+// Update applies changes to the file passed to WatchYUML, if there are any, and returns
+// whether the form needs to be drawn again
+func (f *Form) Update() bool {
+	if f.watcher == nil {
+		return false
+	}
+	select {
+	case result := <-f.watcher.changes:
+		f.watcher.err = f.reload(result)
+		if f.watcher.err != nil {
+			f.watcher.err = fmt.Errorf("Could not load %s:\n%s", f.watcher.path, f.watcher.err.Error())
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// ReloadError returns why the file passed to WatchYUML could not be loaded the last time
+// it changed, or nil if it was loaded
+func (f *Form) ReloadError() error {
+	if f.watcher == nil {
+		return nil
+	}
+	return f.watcher.err
+}
+
+func (f *Form) reload(result watchResult) error {
+	if result.err != nil {
+		return result.err
+	}
+	element, err := yuml.ParseYUMLFile(bytes.NewReader(result.data), f.watcher.path)
+	if err != nil {
+		return err
+	}
+	return f.load(element, f.watcher.handlers, f.source != nil)
+}
+
+// drawReloadError draws the last error from WatchYUML over the whole form
+func (f *Form) drawReloadError() {
+	err := f.ReloadError()
+	if err == nil {
+		return
+	}
+	if f.watcher.errorPage == nil {
+		label := new(builtin.Label)
+		label.SetWrap(true)
+		label.SetPadding(components.Insets{Left: 16, Top: 16, Right: 16, Bottom: 16})
+		f.watcher.errorPage = new(builtin.Page)
+		f.watcher.errorPage.AppendChild(label)
+	}
+	page := f.watcher.errorPage
+	label := page.Children()[0].(*builtin.Label)
+	if label.Content() != err.Error() {
+		label.SetText(err.Error())
+	}
+	page.SetSize(f.renderer.Size())
+
+	f.renderer.FillRect(render.Rect{Width: 1, Height: 1}, ReloadErrorBackground)
+	page.Draw(f.renderer)
+}
+
+// watcher checks a file for changes in the background
+type watcher struct {
+	path     string
+	handlers []interface{}
+	changes  chan watchResult
+	stop     chan struct{}
+
+	// err is the last reload error, errorPage is where it's shown
+	err       error
+	errorPage *builtin.Page
+}
+
+// watchResult is either the new content of a watched file or why it could not be read
+type watchResult struct {
+	data []byte
+	err  error
+}
+
+func (w *watcher) run() {
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+
+	var last watchResult
+	first := true
+	for {
+		data, err := loader.Bytes(w.path)
+		result := watchResult{data, err}
+		if first || changed(last, result) {
+			// Only the latest change matters, replace any that wasn't applied yet
+			select {
+			case <-w.changes:
+			default:
+			}
+			w.changes <- result
+			last, first = result, false
+		}
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func changed(last, current watchResult) bool {
+	if last.err != nil || current.err != nil {
+		return last.err == nil || current.err == nil || last.err.Error() != current.err.Error()
+	}
+	return !bytes.Equal(last.data, current.data)
+}
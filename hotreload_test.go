@@ -0,0 +1,107 @@
+package youi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	resources "gopkg.in/cookieo9/resources-go.v2"
+
+	"github.com/hamcha/youi/components/builtin"
+	"github.com/hamcha/youi/loader"
+)
+
+// writeFile replaces a file all at once, so the watcher never reads half of it
+func writeFile(t *testing.T, path, content string) {
+	if err := ioutil.WriteFile(path+".tmp", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitUpdate calls Update until the watcher sends a change
+func waitUpdate(t *testing.T, form *Form) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !form.Update() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the watched file to be loaded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchYUML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "youi-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldBundles, oldInterval := loader.BundleSequence, WatchInterval
+	loader.BundleSequence = resources.BundleSequence{resources.OpenFS(dir)}
+	WatchInterval = 10 * time.Millisecond
+	defer func() { loader.BundleSequence, WatchInterval = oldBundles, oldInterval }()
+
+	path := filepath.Join(dir, "form.yuml")
+	writeFile(t, path, `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<TextBox Id="name" />
+	<Slider Id="volume" Max="10" />
+</Page>`)
+
+	form := makeTestForm()
+	form.WatchYUML("form.yuml")
+	defer form.StopWatching()
+	waitUpdate(t, form)
+	if err := form.ReloadError(); err != nil {
+		t.Fatalf("Could not load watched file: %s", err.Error())
+	}
+	form.FindByID("name").(*builtin.TextBox).SetValue("typed")
+	form.FindByID("volume").(*builtin.Slider).SetValue(7)
+
+	// Reloading keeps the state of the components that are still there
+	writeFile(t, path, `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Label Id="title" Text="Settings" />
+	<TextBox Id="name" />
+	<Slider Id="volume" Max="10" />
+</Page>`)
+	waitUpdate(t, form)
+	if err := form.ReloadError(); err != nil {
+		t.Fatalf("Could not reload watched file: %s", err.Error())
+	}
+	if form.FindByID("title") == nil {
+		t.Fatal("Expected the new label to be loaded")
+	}
+	name, volume := form.FindByID("name").(*builtin.TextBox), form.FindByID("volume").(*builtin.Slider)
+	if name.Value() != "typed" || volume.Value() != 7 {
+		t.Errorf("Expected state to be kept, got %q and %g", name.Value(), volume.Value())
+	}
+
+	// Errors are kept for drawing, along with the last tree that loaded
+	writeFile(t, path, `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Missing />
+</Page>`)
+	waitUpdate(t, form)
+	if form.ReloadError() == nil {
+		t.Fatal("Expected an error for the broken file")
+	}
+	if form.FindByID("name") != name {
+		t.Error("Expected the old tree to stay after an error")
+	}
+	form.Draw()
+
+	// Fixing the file clears the error
+	writeFile(t, path, `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<TextBox Id="name" />
+</Page>`)
+	waitUpdate(t, form)
+	if err := form.ReloadError(); err != nil {
+		t.Fatalf("Expected the error to clear, got %s", err.Error())
+	}
+	if value := form.FindByID("name").(*builtin.TextBox).Value(); value != "typed" {
+		t.Errorf("Expected text box value to be kept, got %q", value)
+	}
+}
@@ -0,0 +1,85 @@
+package youi
+
+import (
+	"reflect"
+
+	"github.com/kataras/go-errors"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/yuml"
+)
+
+// FieldTag is the struct tag that FillFields looks at
+const FieldTag = "youi"
+
+// ID errors
+var (
+	ErrDuplicateID      = errors.New("Id \"%s\" is used by more than one element")
+	ErrNotStructPointer = errors.New("Fields can only be filled in a pointer to a struct, got %T")
+	ErrIDNotFound       = errors.New("No element with Id \"%s\" for field %s")
+	ErrFieldNotSettable = errors.New("Field %s must be exported to be filled")
+	ErrFieldType        = errors.New("Field %s can't hold element \"%s\" of type %s")
+)
+
+// FindByID returns the component with an Id (or Name) in the form, or nil if there is none
+func (f *Form) FindByID(id string) components.Component {
+	return components.FindByID(f.Root, id)
+}
+
+// FillFields sets the fields of a struct that have a youi tag to the components with the
+// Id in the tag, like:
+//
+//	type Editor struct {
+//		Save *builtin.Button `youi:"saveButton"`
+//	}
+//
+// Fields can be either of the component's type or an interface it implements. Structs
+// given to LoadYUML as handlers have their fields filled automatically.
+func (f *Form) FillFields(target interface{}) error {
+	return fillFields(f.Root, target)
+}
+
+func fillFields(root components.Component, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if !isStructPointer(value) {
+		return ErrNotStructPointer.Format(target)
+	}
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		id, ok := field.Tag.Lookup(FieldTag)
+		if !ok || id == "" {
+			continue
+		}
+		component := components.FindByID(root, id)
+		if component == nil {
+			return ErrIDNotFound.Format(id, field.Name)
+		}
+		if !value.Field(i).CanSet() {
+			return ErrFieldNotSettable.Format(field.Name)
+		}
+		componentValue := reflect.ValueOf(component)
+		if !componentValue.Type().AssignableTo(field.Type) {
+			return ErrFieldType.Format(field.Name, id, componentValue.Type())
+		}
+		value.Field(i).Set(componentValue)
+	}
+	return nil
+}
+
+func isStructPointer(value reflect.Value) bool {
+	return value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Struct
+}
+
+// checkID makes sure no two components outside of templates have the same ID
+func (b *yumlBuilder) checkID(element *yuml.Element, c components.Component) {
+	id := c.ID()
+	if id == "" || b.inTemplate {
+		return
+	}
+	if b.ids[id] {
+		b.errors.Add(element, ErrDuplicateID.Format(id))
+		return
+	}
+	b.ids[id] = true
+}
@@ -0,0 +1,71 @@
+package youi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/components/builtin"
+)
+
+const fieldsYUML = `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<TextBox Id="name" />
+	<Button Id="ok" Text="Ok" />
+</Page>`
+
+func TestFillFields(t *testing.T) {
+	form := makeTestForm()
+	if err := form.LoadYUML(strings.NewReader(fieldsYUML)); err != nil {
+		t.Fatalf("Could not load YUML: %s", err.Error())
+	}
+
+	var fields struct {
+		Name  *builtin.TextBox     `youi:"name"`
+		OK    components.Component `youi:"ok"`
+		Other string
+	}
+	if err := form.FillFields(&fields); err != nil {
+		t.Fatalf("Could not fill fields: %s", err.Error())
+	}
+	if fields.Name == nil || fields.Name != form.FindByID("name") {
+		t.Errorf("Expected Name to be the text box, got %v", fields.Name)
+	}
+	if _, ok := fields.OK.(*builtin.Button); !ok {
+		t.Errorf("Expected OK to be the button, got %T", fields.OK)
+	}
+}
+
+func TestFillFieldsErrors(t *testing.T) {
+	form := makeTestForm()
+	if err := form.LoadYUML(strings.NewReader(fieldsYUML)); err != nil {
+		t.Fatalf("Could not load YUML: %s", err.Error())
+	}
+
+	var missing struct {
+		Save *builtin.Button `youi:"save"`
+	}
+	var unexported struct {
+		ok *builtin.Button `youi:"ok"`
+	}
+	var mismatch struct {
+		OK *builtin.TextBox `youi:"ok"`
+	}
+	tests := []struct {
+		target   interface{}
+		expected string
+	}{
+		{&missing, ErrIDNotFound.Format("save", "Save").Error()},
+		{&unexported, ErrFieldNotSettable.Format("ok").Error()},
+		{&mismatch, ErrFieldType.Format("OK", "ok", "*builtin.Button").Error()},
+		{missing, ErrNotStructPointer.Format(missing).Error()},
+	}
+	for _, test := range tests {
+		err := form.FillFields(test.target)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Expected error %q, got %v", test.expected, err)
+		}
+	}
+	if unexported.ok != nil {
+		t.Error("Unexported field was filled")
+	}
+}
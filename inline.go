@@ -0,0 +1,129 @@
+package youi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/kataras/go-errors"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/components/builtin"
+	"github.com/hamcha/youi/yuml"
+)
+
+// Inline content errors
+var (
+	ErrUnknownInlineElement = errors.New("Unknown inline element \"%s\" (must be Span, Bold, Italic, Underline, Strikethrough or LineBreak)")
+)
+
+// hasInlineContent returns whether an element has text or children to use as inline content
+func hasInlineContent(element *yuml.Element) bool {
+	if len(element.Children) > 0 {
+		return true
+	}
+	for _, text := range element.Text {
+		if strings.TrimSpace(text) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isInlineElement returns whether an element is one of those used in inline content
+func isInlineElement(element *yuml.Element) bool {
+	if element.Name.Space != builtin.Namespace {
+		return false
+	}
+	switch element.Name.Local {
+	case "Span", "Bold", "Italic", "Underline", "Strikethrough", "LineBreak":
+		return true
+	}
+	return false
+}
+
+// makeTextRuns converts the content of an element to styled runs of text. Whitespace is
+// collapsed like in HTML, use <LineBreak /> to start a new line.
+func makeTextRuns(element *yuml.Element) ([]components.TextRun, error) {
+	builder := new(inlineBuilder)
+	err := builder.addElement(element, components.TextStyle{})
+	return builder.runs, err
+}
+
+// inlineBuilder collects runs of text, collapsing whitespace across them
+type inlineBuilder struct {
+	runs []components.TextRun
+	// space is set when whitespace was skipped since the last word
+	space bool
+	// lineStarted is set when the current line has any words
+	lineStarted bool
+}
+
+func (b *inlineBuilder) addElement(element *yuml.Element, style components.TextStyle) error {
+	for index, text := range element.Text {
+		b.addText(text, style)
+		if index >= len(element.Children) {
+			break
+		}
+
+		child := element.Children[index].Element
+		if child.Name.Space != builtin.Namespace {
+			return yuml.ElementError(child, ErrUnknownInlineElement.Format(child.Name.Local))
+		}
+
+		childStyle := style
+		switch child.Name.Local {
+		case "Span":
+			var err error
+			childStyle, err = components.ParseTextStyle(toAttributeList(child.Attributes), style)
+			if err != nil {
+				return yuml.ElementError(child, err)
+			}
+		case "Bold":
+			childStyle.Bold = true
+		case "Italic":
+			childStyle.Italic = true
+		case "Underline":
+			childStyle.Underline = true
+		case "Strikethrough":
+			childStyle.Strikethrough = true
+		case "LineBreak":
+			b.runs = append(b.runs, components.TextRun{Text: "\n", Style: style})
+			b.space = false
+			b.lineStarted = false
+			continue
+		default:
+			return yuml.ElementError(child, ErrUnknownInlineElement.Format(child.Name.Local))
+		}
+
+		if err := b.addElement(child, childStyle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *inlineBuilder) addText(text string, style components.TextStyle) {
+	if text == "" {
+		return
+	}
+	if unicode.IsSpace([]rune(text)[0]) {
+		b.space = true
+	}
+
+	str := ""
+	for _, word := range strings.Fields(text) {
+		if b.space && b.lineStarted {
+			str += " "
+		}
+		str += word
+		b.space = true
+		b.lineStarted = true
+	}
+
+	// Trailing spaces are only added before the next word, if any
+	runes := []rune(text)
+	b.space = unicode.IsSpace(runes[len(runes)-1])
+	if str != "" {
+		b.runs = append(b.runs, components.TextRun{Text: str, Style: style})
+	}
+}
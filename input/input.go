@@ -0,0 +1,42 @@
+package input
+
+// Action is the state change of a key or mouse button
+type Action int
+
+// Key/button actions
+const (
+	Release Action = iota
+	Press
+	Repeat
+)
+
+// MouseButton is a single mouse button
+type MouseButton int
+
+// Mouse buttons (values match GLFW's)
+const (
+	MouseButtonLeft MouseButton = iota
+	MouseButtonRight
+	MouseButtonMiddle
+	MouseButton4
+	MouseButton5
+	MouseButton6
+	MouseButton7
+	MouseButton8
+)
+
+// Modifier is a bitmask of the modifier keys held during a key or mouse event
+type Modifier int
+
+// Modifier keys (values match GLFW's)
+const (
+	ModShift Modifier = 1 << iota
+	ModControl
+	ModAlt
+	ModSuper
+)
+
+// Has returns whether all the given modifiers are set
+func (m Modifier) Has(mod Modifier) bool {
+	return m&mod == mod
+}
@@ -0,0 +1,142 @@
+package input
+
+// Key is a physical keyboard key, values match GLFW key codes so they can be converted directly
+type Key int
+
+// Printable keys
+const (
+	KeyUnknown      Key = -1
+	KeySpace        Key = 32
+	KeyApostrophe   Key = 39
+	KeyComma        Key = 44
+	KeyMinus        Key = 45
+	KeyPeriod       Key = 46
+	KeySlash        Key = 47
+	KeySemicolon    Key = 59
+	KeyEqual        Key = 61
+	KeyLeftBracket  Key = 91
+	KeyBackslash    Key = 92
+	KeyRightBracket Key = 93
+	KeyGraveAccent  Key = 96
+)
+
+// Number keys
+const (
+	Key0 Key = 48 + iota
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+)
+
+// Letter keys
+const (
+	KeyA Key = 65 + iota
+	KeyB
+	KeyC
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyL
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyS
+	KeyT
+	KeyU
+	KeyV
+	KeyW
+	KeyX
+	KeyY
+	KeyZ
+)
+
+// Function and navigation keys
+const (
+	KeyEscape Key = 256 + iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyInsert
+	KeyDelete
+	KeyRight
+	KeyLeft
+	KeyDown
+	KeyUp
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+)
+
+// Lock and system keys
+const (
+	KeyCapsLock Key = 280 + iota
+	KeyScrollLock
+	KeyNumLock
+	KeyPrintScreen
+	KeyPause
+)
+
+// F keys
+const (
+	KeyF1 Key = 290 + iota
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+)
+
+// Keypad keys
+const (
+	KeyKP0 Key = 320 + iota
+	KeyKP1
+	KeyKP2
+	KeyKP3
+	KeyKP4
+	KeyKP5
+	KeyKP6
+	KeyKP7
+	KeyKP8
+	KeyKP9
+	KeyKPDecimal
+	KeyKPDivide
+	KeyKPMultiply
+	KeyKPSubtract
+	KeyKPAdd
+	KeyKPEnter
+	KeyKPEqual
+)
+
+// Modifier keys
+const (
+	KeyLeftShift Key = 340 + iota
+	KeyLeftControl
+	KeyLeftAlt
+	KeyLeftSuper
+	KeyRightShift
+	KeyRightControl
+	KeyRightAlt
+	KeyRightSuper
+	KeyMenu
+)
@@ -15,6 +15,9 @@ import (
 // If you need to use your own bundles, just override it
 var BundleSequence resources.BundleSequence
 
+// ErrNotFound means a file could not be found in any bundle
+var ErrNotFound = resources.ErrNotFound
+
 func init() {
 	BundleSequence = resources.DefaultBundle
 }
@@ -39,7 +42,7 @@ func Open(path string) (io.ReadCloser, error) {
 			return nil, err
 		}
 	}
-	return nil, resources.ErrNotFound
+	return nil, ErrNotFound
 }
 
 // Bytes opens a file from the bundle sequence and tries to read all of its contents immediately
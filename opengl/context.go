@@ -40,7 +40,7 @@ func SetBGColor(col color.Color) {
 
 // Clear clears the screen
 func Clear() {
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
 }
 
 // Poll polls for events and updates all glfw functions
@@ -9,6 +9,7 @@ type Mesh struct {
 	vao       uint32
 	vbo       uint32
 	ebo       uint32
+	program   uint32
 	Shader    *Shader
 	ownshader bool
 }
@@ -75,6 +76,13 @@ func (m *Mesh) Draw() {
 	gl.BindVertexArray(m.vao)
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.ebo)
 
+	// Vertex attributes are stored in the VAO, set them up again if the program changed
+	if m.program != m.Shader.programID {
+		gl.BindBuffer(gl.ARRAY_BUFFER, m.vbo)
+		m.Shader.bindAttributes()
+		m.program = m.Shader.programID
+	}
+
 	// Draw vertices
 	gl.DrawElements(gl.TRIANGLES, int32(len(m.indices)), gl.UNSIGNED_INT, nil)
 
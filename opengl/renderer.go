@@ -0,0 +1,282 @@
+package opengl
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/hamcha/youi/font"
+	"github.com/hamcha/youi/render"
+)
+
+const fillFragmentShader = `
+#version 330 core
+uniform vec4 fillColor;
+in vec2 fragTexCoord;
+out vec4 color;
+void main() {
+	color = fillColor;
+}
+` + "\x00"
+
+const textureFragmentShader = `
+#version 330 core
+uniform sampler2D imgdata;
+in vec2 fragTexCoord;
+out vec4 color;
+void main() {
+	color = texture(imgdata, fragTexCoord);
+}
+` + "\x00"
+
+// clipFragmentShader discards what's outside a rounded rectangle, it's only used to mark
+// clipping areas in the stencil buffer
+const clipFragmentShader = `
+#version 330 core
+uniform vec2 clipSize;
+uniform float clipRadius;
+in vec2 fragTexCoord;
+out vec4 color;
+void main() {
+	// Distance from the closest corner circle center, in pixels
+	vec2 pos = fragTexCoord * clipSize;
+	vec2 corner = max(vec2(clipRadius) - pos, pos - (clipSize - vec2(clipRadius)));
+	if (corner.x > 0 && corner.y > 0 && length(corner) > clipRadius) {
+		discard;
+	}
+	color = vec4(1.0);
+}
+` + "\x00"
+
+// Renderer is a render.Renderer that draws on an OpenGL window
+type Renderer struct {
+	window *Window
+
+	fillQuad    *Mesh
+	textureQuad *Mesh
+	clipQuad    *Mesh
+
+	// clips is the stack of clipping areas, stencil is how many of them are rounded
+	clips   []clipArea
+	stencil int
+}
+
+// clipArea is a clipping rectangle, scissor is its bounding box (intersected with all
+// the ones before) in pixels from the top-left corner
+type clipArea struct {
+	rect    render.Rect
+	radius  float32
+	scissor image.Rectangle
+}
+
+// MakeRenderer creates a renderer for a window, the window's context must be current
+func MakeRenderer(window *Window) *Renderer {
+	fillShader := DefaultShader()
+	err := fillShader.SetFragmentSource(fillFragmentShader)
+	if err != nil {
+		panic(err)
+	}
+
+	textureShader := DefaultShader()
+	err = textureShader.SetFragmentSource(textureFragmentShader)
+	if err != nil {
+		panic(err)
+	}
+
+	clipShader := DefaultShader()
+	err = clipShader.SetFragmentSource(clipFragmentShader)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Renderer{
+		window:      window,
+		fillQuad:    MakeQuad(fillShader),
+		textureQuad: MakeQuad(textureShader),
+		clipQuad:    MakeQuad(clipShader),
+	}
+}
+
+// Size returns the window size
+func (r *Renderer) Size() image.Point {
+	return r.window.GetSize()
+}
+
+// Clear clears the window with its background color
+func (r *Renderer) Clear() {
+	r.window.Clear()
+}
+
+// Present swaps the window buffers
+func (r *Renderer) Present() {
+	r.window.DrawDone()
+}
+
+// FillRect draws a solid colored rectangle
+func (r *Renderer) FillRect(rect render.Rect, col color.Color) {
+	r.fillQuad.Shader.GetUniform("transform").Set(quadTransform(rect))
+	r.fillQuad.Shader.GetUniform("fillColor").Set(col)
+	r.fillQuad.Draw()
+}
+
+// DrawTexture draws a texture stretched over a rectangle
+func (r *Renderer) DrawTexture(tex render.Texture, rect render.Rect) {
+	r.textureQuad.Shader.GetUniform("transform").Set(quadTransform(rect))
+	r.textureQuad.Shader.GetUniform("imgdata").Set(tex.(*Texture))
+	r.textureQuad.Draw()
+}
+
+// DrawText draws text starting from the top-left corner of a rectangle
+func (r *Renderer) DrawText(txt render.Text, rect render.Rect) {
+	text := txt.(*Text)
+	text.Shader.GetUniform("transform").Set(textTransform(rect, text.Scale(), r.Size()))
+	text.Draw()
+
+	// Underlines and strikethroughs are plain rectangles
+	if text.layout == nil {
+		return
+	}
+	surface := r.Size()
+	w, h := float32(surface.X), float32(surface.Y)
+	scale := text.Scale()
+	for _, decoration := range text.layout.Decorations {
+		r.FillRect(render.Rect{
+			X:      rect.X + decoration.Bounds.X*scale/w,
+			Y:      rect.Y + decoration.Bounds.Y*scale/h,
+			Width:  decoration.Bounds.Width * scale / w,
+			Height: decoration.Bounds.Height * scale / h,
+		}, text.spanColor(text.layout.Spans[decoration.Span]))
+	}
+}
+
+// PushClip restricts drawing to a rectangle (intersected with the current one) using the
+// scissor test
+func (r *Renderer) PushClip(rect render.Rect) {
+	r.PushRoundedClip(rect, 0)
+}
+
+// PushRoundedClip restricts drawing to a rounded rectangle. The scissor test takes care of
+// its bounding box, the corners are masked with the stencil buffer: every rounded area
+// increments the stencil value inside of it, and only pixels inside all of them (where the
+// value is the number of rounded areas) are drawn.
+func (r *Renderer) PushRoundedClip(rect render.Rect, radius float32) {
+	px := rect.Pixels(r.Size())
+	area := clipArea{
+		rect:    rect,
+		radius:  radius,
+		scissor: image.Rect(int(px.X+0.5), int(px.Y+0.5), int(px.X+px.Width+0.5), int(px.Y+px.Height+0.5)),
+	}
+	if len(r.clips) > 0 {
+		area.scissor = area.scissor.Intersect(r.clips[len(r.clips)-1].scissor)
+	}
+	r.clips = append(r.clips, area)
+	r.applyScissor()
+
+	if radius > 0 {
+		r.drawStencil(area, gl.INCR)
+		r.stencil++
+		r.applyStencil()
+	}
+}
+
+// PopClip goes back to the clipping area before the last PushClip or PushRoundedClip
+func (r *Renderer) PopClip() {
+	if len(r.clips) == 0 {
+		return
+	}
+	area := r.clips[len(r.clips)-1]
+	if area.radius > 0 {
+		r.drawStencil(area, gl.DECR)
+		r.stencil--
+		r.applyStencil()
+	}
+	r.clips = r.clips[:len(r.clips)-1]
+	r.applyScissor()
+}
+
+// drawStencil changes the stencil value inside a rounded area, only where all the areas
+// before it apply (when popping, that's where the area itself incremented it)
+func (r *Renderer) drawStencil(area clipArea, op uint32) {
+	if r.stencil == 0 {
+		gl.Enable(gl.STENCIL_TEST)
+	}
+	gl.StencilFunc(gl.EQUAL, int32(r.stencil), 0xff)
+	// The mask is drawn regardless of depth, it's not part of the scene
+	gl.StencilOp(gl.KEEP, op, op)
+	gl.ColorMask(false, false, false, false)
+	gl.DepthMask(false)
+
+	px := area.rect.Pixels(r.Size())
+	shader := r.clipQuad.Shader
+	shader.GetUniform("transform").Set(quadTransform(area.rect))
+	shader.GetUniform("clipSize").Set([]float32{px.Width, px.Height})
+	shader.GetUniform("clipRadius").Set(area.radius)
+	r.clipQuad.Draw()
+
+	gl.ColorMask(true, true, true, true)
+	gl.DepthMask(true)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+}
+
+// applyStencil only lets pixels inside all rounded areas through
+func (r *Renderer) applyStencil() {
+	if r.stencil == 0 {
+		gl.Disable(gl.STENCIL_TEST)
+		return
+	}
+	gl.Enable(gl.STENCIL_TEST)
+	gl.StencilFunc(gl.EQUAL, int32(r.stencil), 0xff)
+}
+
+func (r *Renderer) applyScissor() {
+	if len(r.clips) == 0 {
+		gl.Disable(gl.SCISSOR_TEST)
+		return
+	}
+
+	// OpenGL counts from the bottom-left corner
+	clip := r.clips[len(r.clips)-1].scissor
+	height := r.Size().Y
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(int32(clip.Min.X), int32(height-clip.Max.Y), int32(clip.Dx()), int32(clip.Dy()))
+}
+
+// MakeTexture uploads an image as texture
+func (r *Renderer) MakeTexture(img *image.RGBA) render.Texture {
+	return MakeTexture(img, TextureOptions{
+		WrapS:     TextureWrapClamp,
+		WrapR:     TextureWrapClamp,
+		MinFilter: TextureFilterLinear,
+		MagFilter: TextureFilterLinear,
+	})
+}
+
+// MakeText creates an empty text mesh for a font
+func (r *Renderer) MakeText(fnt *font.Font) render.Text {
+	return MakeText(fnt, "")
+}
+
+// quadTransform returns a matrix that maps the default quad (-1..1) to a rectangle
+func quadTransform(rect render.Rect) mgl32.Mat4 {
+	// Change pivot to top-left instead of center
+	x, y := rect.X+rect.Width/2, rect.Y+rect.Height/2
+
+	// Set position, taking into account OpenGL's weird coordinate system
+	posMtx := mgl32.Translate3D((x*2)-1, -(y*2)+1, 0.0)
+
+	// Scale to size
+	sizeMtx := mgl32.Scale3D(rect.Width, rect.Height, 1.0)
+
+	// Multiply everything into a transform matrix
+	return posMtx.Mul4(sizeMtx)
+}
+
+// textTransform returns a matrix that maps text vertices (in texture pixels, Y going down)
+// to the screen, starting from the rectangle's top-left corner
+func textTransform(rect render.Rect, scale float32, surface image.Point) mgl32.Mat4 {
+	posMtx := mgl32.Translate3D((rect.X*2)-1, -(rect.Y*2)+1, 0.0)
+	sizeMtx := mgl32.Scale3D(scale*2/float32(surface.X), -scale*2/float32(surface.Y), 1.0)
+	return posMtx.Mul4(sizeMtx)
+}
@@ -181,28 +181,24 @@ func (s *Shader) compileProgram() error {
 		return fmt.Errorf("link failed: %v", log)
 	}
 
-	// Reset attributes
-	if s.vertAttrib != 0 {
-		gl.DisableVertexAttribArray(s.vertAttrib)
+	// Reset all uniforms
+	for uname := range s.uniforms {
+		s.uniforms[uname].id = -1
+		s.uniforms[uname].program = s.programID
 	}
+
+	return nil
+}
+
+// bindAttributes sets up the vertex attributes for the currently bound vertex array
+func (s *Shader) bindAttributes() {
 	s.vertAttrib = uint32(gl.GetAttribLocation(s.programID, glString("vert")))
 	gl.EnableVertexAttribArray(s.vertAttrib)
 	gl.VertexAttribPointer(s.vertAttrib, 3, gl.FLOAT, false, 5*4, gl.PtrOffset(0))
 
-	if s.texCoordAttrib != 0 {
-		gl.DisableVertexAttribArray(s.texCoordAttrib)
-	}
 	s.texCoordAttrib = uint32(gl.GetAttribLocation(s.programID, glString("vertTexCoord")))
 	gl.EnableVertexAttribArray(s.texCoordAttrib)
 	gl.VertexAttribPointer(s.texCoordAttrib, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
-
-	// Reset all uniforms
-	for uname := range s.uniforms {
-		s.uniforms[uname].id = -1
-		s.uniforms[uname].program = s.programID
-	}
-
-	return nil
 }
 
 func setShader(src string, shaderType uint32) (uint32, error) {
@@ -4,70 +4,241 @@ import (
 	"image/color"
 
 	"github.com/hamcha/youi/font"
-	"golang.org/x/image/math/fixed"
 )
 
+// Text is a set of glyph meshes (one per span and font atlas page) drawn with a font shader
 type Text struct {
-	font    *font.Font
+	font   *font.Font
+	layout *font.TextLayout
+	size   float64
+	color  color.Color
+	Shader *Shader
+	meshes []textMesh
+}
+
+// textMesh holds the glyphs of a span that are on the same font atlas page
+type textMesh struct {
+	span int
+	page *font.Page
+	mesh *Mesh
+}
+
+// textMeshKey identifies the glyphs that can be drawn together
+type textMeshKey struct {
+	span, page int
+}
+
+// pageTexture is an uploaded font atlas page, shared by all the texts that use it
+type pageTexture struct {
 	texture *Texture
-	content string
-	Shader  *Shader
-	Mesh    *Mesh
+	version int
+	// refs is how many text meshes use the page, the texture is deleted when it gets to zero
+	refs int
 }
 
+// pageTextures holds the textures of all font pages in use by texts
+var pageTextures = make(map[*font.Page]*pageTexture)
+
+// retainPage marks a font page as used by one more text mesh, uploading it if needed
+func retainPage(page *font.Page) {
+	tex, ok := pageTextures[page]
+	if !ok {
+		tex = &pageTexture{
+			texture: MakeTexture(page.Image, TextureOptions{
+				WrapS:     TextureWrapClamp,
+				WrapR:     TextureWrapClamp,
+				MinFilter: TextureFilterLinear,
+				MagFilter: TextureFilterLinear,
+			}),
+			version: page.Version,
+		}
+		pageTextures[page] = tex
+	}
+	tex.refs++
+}
+
+// releasePage undoes retainPage, deleting the page texture once no mesh uses it
+func releasePage(page *font.Page) {
+	tex, ok := pageTextures[page]
+	if !ok {
+		return
+	}
+	tex.refs--
+	if tex.refs <= 0 {
+		tex.texture.Destroy()
+		delete(pageTextures, page)
+	}
+}
+
+// getPageTexture returns the texture for a retained font page, uploading it again if
+// glyphs were added to it since the last time
+func getPageTexture(page *font.Page) *Texture {
+	tex := pageTextures[page]
+	if tex.version != page.Version {
+		tex.texture.Update(page.Image)
+		tex.version = page.Version
+	}
+	return tex.texture
+}
+
+// MakeText lays out a string with a font and creates a mesh for it
 func MakeText(fnt *font.Font, text string) *Text {
-	vertices, indices := quadFromText(fnt, text)
-	texture := MakeTexture(fnt.Texture, TextureOptions{
-		WrapS:     TextureWrapClamp,
-		WrapR:     TextureWrapClamp,
-		MinFilter: TextureFilterLinear,
-		MagFilter: TextureFilterLinear,
-	})
-	fontShader := getFontShader(texture, fnt)
-	mesh := MakeMesh(vertices, indices, fontShader)
-	return &Text{
-		font:    fnt,
-		content: text,
-		texture: texture,
-		Shader:  fontShader,
-		Mesh:    mesh,
+	t := &Text{
+		font:   fnt,
+		size:   float64(fnt.Size),
+		color:  color.White,
+		Shader: getFontShader(),
 	}
+	t.SetContent(text)
+	return t
 }
 
+// SetContent lays out a text without wrapping and regenerates the mesh
 func (t *Text) SetContent(text string) {
-	t.Mesh.Destroy()
-	vertices, indices := quadFromText(t.font, text)
-	t.Mesh = MakeMesh(vertices, indices, t.Shader)
+	t.SetLayout(t.font.Layout(text, font.LayoutOptions{}))
+}
+
+// SetLayout replaces the laid out text and regenerates the meshes
+func (t *Text) SetLayout(layout *font.TextLayout) {
+	t.destroyMeshes()
+	t.layout = layout
+
+	// Split glyphs by span and atlas page, each needs its own draw call
+	groups := make(map[textMeshKey][]font.Glyph)
+	baselines := make(map[textMeshKey][]float32)
+	for _, line := range layout.Lines {
+		for _, glyph := range layout.Glyphs[line.Start:line.End] {
+			if glyph.Atlas.Width > 0 {
+				key := textMeshKey{glyph.Span, glyph.Page}
+				groups[key] = append(groups[key], glyph)
+				baselines[key] = append(baselines[key], line.Baseline)
+			}
+		}
+	}
+	for key, glyphs := range groups {
+		span := layout.Spans[key.span]
+		slant := float32(0)
+		if span.Italic {
+			slant = font.ItalicSlant
+		}
+		vertices, indices := quadFromText(glyphs, baselines[key], slant)
+		page := span.Font.Pages[key.page]
+		retainPage(page)
+		t.meshes = append(t.meshes, textMesh{
+			span: key.span,
+			page: page,
+			mesh: MakeMesh(vertices, indices, t.Shader),
+		})
+	}
 }
 
+// Destroy frees up the meshes and shader of the text, and the font page textures no other
+// text uses. The text can't be drawn afterwards.
+func (t *Text) Destroy() {
+	t.destroyMeshes()
+	t.Shader.Destroy()
+}
+
+func (t *Text) destroyMeshes() {
+	for _, mesh := range t.meshes {
+		mesh.mesh.Destroy()
+		releasePage(mesh.page)
+	}
+	t.meshes = nil
+}
+
+// SetColor sets the text color, for spans that don't have their own
 func (t *Text) SetColor(col color.Color) {
-	t.Shader.GetUniform("fontColor").Set(col)
+	t.color = col
 }
 
-const fontVertexShader = `
-#version 330 core
-uniform mat4 transform;
-uniform float ttfScale;
-in vec3 vert;
-in vec2 vertTexCoord;
-out vec2 fragTexCoord;
-void main() {
-	fragTexCoord = vertTexCoord;
-	gl_Position = transform * vec4(vert * ttfScale * 0.2, 1);
+// SetSize sets the font size in pixels
+func (t *Text) SetSize(size float64) {
+	t.size = size
+}
+
+// Scale returns the ratio between the wanted font size and the font texture size
+func (t *Text) Scale() float32 {
+	return float32(t.size) / float32(t.font.Size)
+}
+
+// Layout returns the laid out text, measures in it are in font texture pixels (see Scale)
+func (t *Text) Layout() *font.TextLayout {
+	return t.layout
+}
+
+// Size returns the size of the text on screen, in pixels
+func (t *Text) Size() (width, height float32) {
+	return t.layout.Width * t.Scale(), t.layout.Height * t.Scale()
+}
+
+// LineBox returns the rectangle taken by a line on screen, relative to the text origin
+func (t *Text) LineBox(line int) font.Rect {
+	return t.scaleRect(t.layout.LineBox(line))
+}
+
+// GlyphRect returns the cell taken by a glyph on screen, relative to the text origin
+func (t *Text) GlyphRect(glyph int) font.Rect {
+	return t.scaleRect(t.layout.GlyphRect(glyph))
+}
+
+// CaretAt returns where the caret goes before the character at a byte offset, in pixels
+// relative to the text origin
+func (t *Text) CaretAt(index int) font.Caret {
+	caret := t.layout.CaretAt(index)
+	scale := t.Scale()
+	caret.X, caret.Y, caret.Height = caret.X*scale, caret.Y*scale, caret.Height*scale
+	return caret
+}
+
+// IndexAt returns the byte offset of the character under a point, in pixels relative to
+// the text origin (see font.TextLayout.IndexAt)
+func (t *Text) IndexAt(x, y float32) int {
+	scale := t.Scale()
+	return t.layout.IndexAt(x/scale, y/scale)
+}
+
+func (t *Text) scaleRect(rect font.Rect) font.Rect {
+	scale := t.Scale()
+	return font.Rect{X: rect.X * scale, Y: rect.Y * scale, Width: rect.Width * scale, Height: rect.Height * scale}
+}
+
+// Draw draws the text meshes, if there is anything to draw
+func (t *Text) Draw() {
+	for _, mesh := range t.meshes {
+		span := t.layout.Spans[mesh.span]
+		weight := float32(0)
+		if span.Bold {
+			weight = font.BoldWeight
+		}
+		t.Shader.GetUniform("fontTexture").Set(getPageTexture(mesh.page))
+		t.Shader.GetUniform("fontColor").Set(t.spanColor(span))
+		t.Shader.GetUniform("fontWeight").Set(weight)
+		mesh.mesh.Draw()
+	}
+}
+
+// spanColor returns the color of a span, which is the text color unless the span has its own
+func (t *Text) spanColor(span font.Span) color.Color {
+	if span.Color != nil {
+		return span.Color
+	}
+	return t.color
 }
-` + "\x00"
 
 const fontFragmentShader = `
 #version 330 core
 uniform sampler2D fontTexture;
 uniform vec4 fontColor;
+uniform float fontWeight;
 in vec2 fragTexCoord;
 out vec4 color;
 
 const float tolerance = 0.05;
 
 void main() {
-	float distance = texture(fontTexture, fragTexCoord).a;
+	// Texture coordinates are in pixels, so that pages can grow
+	float distance = texture(fontTexture, fragTexCoord / textureSize(fontTexture, 0)).a + fontWeight;
 	float w1 = smoothstep(0.5-tolerance, 0.5+tolerance, distance);
 	float w2 = smoothstep(0.5-tolerance*2, 0.5+tolerance*2, distance);
 	float alpha = (w1 + w2) / 2.0;
@@ -75,7 +246,7 @@ void main() {
 }
 ` + "\x00"
 
-func getFontShader(texture *Texture, font *font.Font) *Shader {
+func getFontShader() *Shader {
 	// Make shader
 	fontShader := DefaultShader()
 
@@ -84,22 +255,16 @@ func getFontShader(texture *Texture, font *font.Font) *Shader {
 	if err != nil {
 		panic(err)
 	}
-	err = fontShader.SetVertexSource(fontVertexShader)
-	if err != nil {
-		panic(err)
-	}
 
-	fontShader.GetUniform("fontTexture").Set(texture)
 	fontShader.GetUniform("fontColor").Set(color.White)
-	fontShader.GetUniform("ttfScale").Set(1.0 / float32(font.Size))
+	fontShader.GetUniform("fontWeight").Set(float32(0))
 
 	// Return it
 	return fontShader
 }
 
-func quadFromText(fnt *font.Font, text string) (vertices []float32, indices []uint32) {
-	//TODO Word wrapping
-
+// quadFromText makes a quad for each glyph, slanting them around their baseline for italics
+func quadFromText(glyphs []font.Glyph, baselines []float32, slant float32) (vertices []float32, indices []uint32) {
 	// Each glyph is a quad
 	// Each quad is 2 triangles / 6 vertices
 	// Each vertex is 5 values
@@ -107,56 +272,43 @@ func quadFromText(fnt *font.Font, text string) (vertices []float32, indices []ui
 	// X2 Y2 Z2 U2 V2 (top right)
 	// X3 Y3 Z3 U3 V3 (bottom left)
 	// X4 Y4 Z4 U4 V4 (bottom right)
-
-	// Get texture size
-	tsize := fnt.Texture.Bounds().Size()
-	twidth, theight := float32(tsize.X), float32(tsize.Y)
+	// Coordinates are in texture pixels with Y going down, the transform takes care of the rest
+	// UVs are in atlas page pixels, the shader normalizes them
 
 	// Make arrays
-	vertices = make([]float32, 20*len(text))
-	indices = make([]uint32, 6*len(text))
-
-	// Get font scale and other TTF parameters
-	fscale := fixed.Int26_6(fnt.Size << 6)
-	prevCharIndex, isFirst := fnt.TTF.Index(0), true
-
-	curx := float32(0)
-	cury := float32(0)
-	for index, chr := range text {
-		// Increase space by whatever kerning is
-		curCharIndex := fnt.TTF.Index(chr)
-		if !isFirst {
-			kern := fnt.TTF.Kern(fscale, prevCharIndex, curCharIndex)
-			//kern = (kern + 32) &^ 63 // ??
-			curx += float32(kern) / 64
-		}
+	vertices = make([]float32, 20*len(glyphs))
+	indices = make([]uint32, 6*len(glyphs))
 
-		// Get quad width/height
-		bounds := fnt.Atlas[chr]
-		size := bounds.Size()
+	for index, glyph := range glyphs {
+		quad := glyph.Bounds
+		atlas := glyph.Atlas
 
-		leftu := float32(bounds.Min.X) / twidth
-		rightu := float32(bounds.Max.X) / twidth
-		topv := float32(bounds.Min.Y) / theight
-		bottomv := float32(bounds.Max.Y) / theight
+		leftu := atlas.X
+		rightu := atlas.X + atlas.Width
+		topv := atlas.Y
+		bottomv := atlas.Y + atlas.Height
+
+		// Top and bottom get pushed in opposite directions when slanting
+		topx := quad.X + (baselines[index]-quad.Y)*slant
+		bottomx := quad.X + (baselines[index]-quad.Y-quad.Height)*slant
 
 		vertidx := index * 20
-		vertices[vertidx+0] = curx                    // X1
-		vertices[vertidx+1] = cury                    // Y1
-		vertices[vertidx+3] = leftu                   // U1
-		vertices[vertidx+4] = bottomv                 // V1
-		vertices[vertidx+5] = curx + float32(size.X)  // X2
-		vertices[vertidx+6] = cury                    // Y2
-		vertices[vertidx+8] = rightu                  // U2
-		vertices[vertidx+9] = bottomv                 // V2
-		vertices[vertidx+10] = curx                   // X3
-		vertices[vertidx+11] = cury + float32(size.Y) // Y3
-		vertices[vertidx+13] = leftu                  // U3
-		vertices[vertidx+14] = topv                   // V3
-		vertices[vertidx+15] = curx + float32(size.X) // X4
-		vertices[vertidx+16] = cury + float32(size.Y) // Y4
-		vertices[vertidx+18] = rightu                 // U4
-		vertices[vertidx+19] = topv                   // V4
+		vertices[vertidx+0] = topx                  // X1
+		vertices[vertidx+1] = quad.Y                // Y1
+		vertices[vertidx+3] = leftu                 // U1
+		vertices[vertidx+4] = topv                  // V1
+		vertices[vertidx+5] = topx + quad.Width     // X2
+		vertices[vertidx+6] = quad.Y                // Y2
+		vertices[vertidx+8] = rightu                // U2
+		vertices[vertidx+9] = topv                  // V2
+		vertices[vertidx+10] = bottomx              // X3
+		vertices[vertidx+11] = quad.Y + quad.Height // Y3
+		vertices[vertidx+13] = leftu                // U3
+		vertices[vertidx+14] = bottomv              // V3
+		vertices[vertidx+15] = bottomx + quad.Width // X4
+		vertices[vertidx+16] = quad.Y + quad.Height // Y4
+		vertices[vertidx+18] = rightu               // U4
+		vertices[vertidx+19] = bottomv              // V4
 
 		ididx := index * 6
 		basevtx := uint32(index * 4)
@@ -166,15 +318,6 @@ func quadFromText(fnt *font.Font, text string) (vertices []float32, indices []ui
 		indices[ididx+3] = basevtx + 1
 		indices[ididx+4] = basevtx + 2
 		indices[ididx+5] = basevtx + 3
-
-		// Get font metrics for advancement
-		metrics := fnt.TTF.HMetric(fscale, curCharIndex)
-		advanceWidth := float32(metrics.AdvanceWidth) / 64
-
-		curx += advanceWidth
-
-		// Set index as previous
-		prevCharIndex, isFirst = curCharIndex, false
 	}
 
 	return
@@ -11,6 +11,7 @@ import (
 type Texture struct {
 	handle uint32
 	unit   uint32
+	size   image.Point
 }
 
 // TextureOptions contains extra options for setting up a texture
@@ -48,7 +49,9 @@ var (
 
 // MakeTexture creates an OpenGL texture and returns it, if possible
 func MakeTexture(img *image.RGBA, options TextureOptions) *Texture {
-	texture := new(Texture)
+	texture := &Texture{
+		size: img.Rect.Size(),
+	}
 
 	// Generate texture handle
 	gl.GenTextures(1, &texture.handle)
@@ -85,6 +88,11 @@ func MakeTexture(img *image.RGBA, options TextureOptions) *Texture {
 	return texture
 }
 
+// Size returns the texture size in pixels
+func (t *Texture) Size() image.Point {
+	return t.size
+}
+
 // Bind binds the texture to a hardware texture unit
 func (t *Texture) Bind(unit uint32) {
 	t.unit = gl.TEXTURE0 + unit
@@ -107,3 +115,23 @@ func (t *Texture) SetUniform(uloc int32) error {
 	gl.Uniform1i(uloc, int32(t.unit-gl.TEXTURE0))
 	return nil
 }
+
+// Update replaces the texture contents with a new image, which can be of a different size
+func (t *Texture) Update(img *image.RGBA) {
+	t.size = img.Rect.Size()
+
+	t.Bind(0)
+	defer t.Unbind()
+
+	width := int32(t.size.X)
+	height := int32(t.size.Y)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+}
+
+// Destroy frees up the texture memory and makes the texture unusable
+func (t *Texture) Destroy() {
+	if t.handle != 0 {
+		gl.DeleteTextures(1, &t.handle)
+		t.handle = 0
+	}
+}
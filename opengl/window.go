@@ -8,6 +8,8 @@ import (
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
+
+	"github.com/hamcha/youi/input"
 )
 
 // Window is a system window with an OpenGL context inside it
@@ -37,6 +39,8 @@ func CreateWindow(width, height int, title string, monitor *glfw.Monitor, parent
 		resizable = glfw.True
 	}
 	glfw.WindowHint(glfw.Resizable, resizable)
+	// Rounded clipping areas are masked with the stencil buffer
+	glfw.WindowHint(glfw.StencilBits, 8)
 
 	// Create window
 	window, err := glfw.CreateWindow(width, height, title, monitor, parentWnd)
@@ -105,6 +109,75 @@ func (w *Window) SetResizeCallback(fn WindowResizeCallback) {
 	})
 }
 
+// WindowCursorCallback is a callback that can be called when the mouse cursor moves inside the window
+type WindowCursorCallback func(x, y float64)
+
+// SetCursorCallback sets a callback to be called when the mouse cursor moves
+func (w *Window) SetCursorCallback(fn WindowCursorCallback) {
+	w.handle.SetCursorPosCallback(func(w *glfw.Window, x, y float64) {
+		fn(x, y)
+	})
+}
+
+// GetCursorPosition returns the last known position of the mouse cursor, relative to the window
+func (w *Window) GetCursorPosition() (float64, float64) {
+	return w.handle.GetCursorPos()
+}
+
+// WindowButtonCallback is a callback that can be called when a mouse button is pressed or released
+type WindowButtonCallback func(button input.MouseButton, action input.Action, mods input.Modifier)
+
+// SetButtonCallback sets a callback to be called when a mouse button is pressed or released
+func (w *Window) SetButtonCallback(fn WindowButtonCallback) {
+	w.handle.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		fn(input.MouseButton(button), input.Action(action), input.Modifier(mods))
+	})
+}
+
+// WindowScrollCallback is a callback that can be called when the mouse wheel (or touchpad) is scrolled
+type WindowScrollCallback func(dx, dy float64)
+
+// SetScrollCallback sets a callback to be called when the user scrolls
+func (w *Window) SetScrollCallback(fn WindowScrollCallback) {
+	w.handle.SetScrollCallback(func(w *glfw.Window, dx, dy float64) {
+		fn(dx, dy)
+	})
+}
+
+// WindowKeyCallback is a callback that can be called when a keyboard key is pressed, repeated or released
+type WindowKeyCallback func(key input.Key, action input.Action, mods input.Modifier)
+
+// SetKeyCallback sets a callback to be called when a keyboard key changes state
+func (w *Window) SetKeyCallback(fn WindowKeyCallback) {
+	w.handle.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		fn(input.Key(key), input.Action(action), input.Modifier(mods))
+	})
+}
+
+// WindowCharCallback is a callback that can be called when the user types a character
+type WindowCharCallback func(char rune)
+
+// SetCharCallback sets a callback to be called when a character is typed (after keyboard layout and IME processing)
+func (w *Window) SetCharCallback(fn WindowCharCallback) {
+	w.handle.SetCharCallback(func(w *glfw.Window, char rune) {
+		fn(char)
+	})
+}
+
+// ClipboardText returns the text in the system clipboard, or an empty string if there is none
+func (w *Window) ClipboardText() string {
+	str, err := w.handle.GetClipboardString()
+	if err != nil {
+		return ""
+	}
+	return str
+}
+
+// SetClipboardText puts text in the system clipboard
+func (w *Window) SetClipboardText(str string) {
+	w.handle.SetClipboardString(str)
+}
+
 var debugType = map[uint32]string{
 	gl.DEBUG_TYPE_ERROR:               "Error",
 	gl.DEBUG_TYPE_MARKER:              "Marker",
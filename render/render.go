@@ -0,0 +1,83 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hamcha/youi/font"
+)
+
+// Rect is a rectangle relative to the drawing surface, where (0, 0) is the
+// top-left corner and (1, 1) is the bottom-right one
+type Rect struct {
+	X, Y, Width, Height float32
+}
+
+// Pixels converts the rectangle to pixel coordinates on a surface of the given size
+func (r Rect) Pixels(surface image.Point) font.Rect {
+	w, h := float32(surface.X), float32(surface.Y)
+	return font.Rect{
+		X:      r.X * w,
+		Y:      r.Y * h,
+		Width:  r.Width * w,
+		Height: r.Height * h,
+	}
+}
+
+// Texture is an image that has been uploaded to a renderer
+type Texture interface {
+	// Size returns the texture size in pixels
+	Size() image.Point
+}
+
+// Text is a string laid out with a font, ready to be drawn by a renderer
+type Text interface {
+	// SetLayout replaces the glyphs to draw, see font.Font.Layout
+	SetLayout(*font.TextLayout)
+	SetColor(color.Color)
+	// SetSize sets the font size, in pixels
+	SetSize(float64)
+	// Destroy frees up the resources held by the text, which can't be used afterwards
+	Destroy()
+}
+
+// Renderer is a drawing backend, components use it to draw themselves without
+// knowing whether they end up on an OpenGL window or in an image
+type Renderer interface {
+	// Size returns the surface size in pixels
+	Size() image.Point
+
+	// Clear fills the whole surface with the background color
+	Clear()
+	// Present shows what has been drawn since the last Clear
+	Present()
+
+	// FillRect draws a solid colored rectangle
+	FillRect(Rect, color.Color)
+	// DrawTexture draws a texture stretched over a rectangle
+	DrawTexture(Texture, Rect)
+	// DrawText draws text starting from the top-left corner of a rectangle
+	DrawText(Text, Rect)
+
+	// PushClip restricts drawing to a rectangle (intersected with the current one, if any)
+	// until the matching PopClip
+	PushClip(Rect)
+	// PushRoundedClip works like PushClip, but the rectangle has rounded corners with the
+	// given radius (in pixels)
+	PushRoundedClip(rect Rect, radius float32)
+	// PopClip goes back to the clipping area before the last PushClip or PushRoundedClip
+	PopClip()
+
+	MakeTexture(*image.RGBA) Texture
+	MakeText(*font.Font) Text
+}
+
+// DrawOutline draws the border of a rectangle with FillRect, thickness is in pixels
+func DrawOutline(r Renderer, rect Rect, col color.Color, thickness float32) {
+	size := r.Size()
+	w, h := thickness/float32(size.X), thickness/float32(size.Y)
+	r.FillRect(Rect{X: rect.X, Y: rect.Y, Width: rect.Width, Height: h}, col)
+	r.FillRect(Rect{X: rect.X, Y: rect.Y + rect.Height - h, Width: rect.Width, Height: h}, col)
+	r.FillRect(Rect{X: rect.X, Y: rect.Y + h, Width: w, Height: rect.Height - 2*h}, col)
+	r.FillRect(Rect{X: rect.X + rect.Width - w, Y: rect.Y + h, Width: w, Height: rect.Height - 2*h}, col)
+}
@@ -0,0 +1,53 @@
+package software
+
+import (
+	"image"
+	"math"
+)
+
+// maskCorners puts back what was under a rounded clipping area outside of its corners,
+// blending on the edge so that corners are antialiased
+func maskCorners(surface *image.RGBA, area clipArea) {
+	shape, radius := area.shape, area.radius
+	for y := area.rect.Min.Y; y < area.rect.Max.Y; y++ {
+		for x := area.rect.Min.X; x < area.rect.Max.X; x++ {
+			coverage := roundedCoverage(shape.X, shape.Y, shape.X+shape.Width, shape.Y+shape.Height, radius, float32(x)+0.5, float32(y)+0.5)
+			if coverage >= 1 {
+				continue
+			}
+			i, j := surface.PixOffset(x, y), area.saved.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				drawn, saved := float32(surface.Pix[i+c]), float32(area.saved.Pix[j+c])
+				surface.Pix[i+c] = uint8(saved + (drawn-saved)*coverage + 0.5)
+			}
+		}
+	}
+}
+
+// roundedCoverage returns how much of a pixel (by its center) is inside a rounded rectangle
+func roundedCoverage(left, top, right, bottom, radius, x, y float32) float32 {
+	// Distance from the closest corner circle center, if in a corner
+	cx, cy := float32(0), float32(0)
+	if x < left+radius {
+		cx = left + radius - x
+	} else if x > right-radius {
+		cx = x - right + radius
+	}
+	if y < top+radius {
+		cy = top + radius - y
+	} else if y > bottom-radius {
+		cy = y - bottom + radius
+	}
+	if cx == 0 || cy == 0 {
+		return 1
+	}
+	distance := float32(math.Sqrt(float64(cx*cx+cy*cy))) - radius
+	coverage := 0.5 - distance
+	if coverage < 0 {
+		return 0
+	}
+	if coverage > 1 {
+		return 1
+	}
+	return coverage
+}
@@ -0,0 +1,240 @@
+package software
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/hamcha/youi/font"
+	"github.com/hamcha/youi/render"
+)
+
+// Renderer is a render.Renderer that rasterizes everything into an in-memory image,
+// so it can be used without a GPU or a display (eg. for tests)
+type Renderer struct {
+	surface    *image.RGBA
+	background color.Color
+	clips      []clipArea
+}
+
+// clipArea is a clipping rectangle, rect is intersected with all the ones before. Rounded
+// areas keep a copy of what was under them, to put back what was drawn on the corners.
+type clipArea struct {
+	rect   image.Rectangle
+	shape  font.Rect
+	radius float32
+	saved  *image.RGBA
+}
+
+// MakeRenderer creates a software renderer with a surface of the given size
+func MakeRenderer(width, height int, background color.Color) *Renderer {
+	return &Renderer{
+		surface:    image.NewRGBA(image.Rect(0, 0, width, height)),
+		background: background,
+	}
+}
+
+// Image returns the surface everything is drawn on
+func (r *Renderer) Image() *image.RGBA {
+	return r.surface
+}
+
+// Resize replaces the surface with an empty one of a different size
+func (r *Renderer) Resize(width, height int) {
+	r.surface = image.NewRGBA(image.Rect(0, 0, width, height))
+}
+
+// Size returns the surface size
+func (r *Renderer) Size() image.Point {
+	return r.surface.Rect.Size()
+}
+
+// Clear fills the surface with the background color (or transparent, if none was set)
+func (r *Renderer) Clear() {
+	var bg color.Color = color.Transparent
+	if r.background != nil {
+		bg = r.background
+	}
+	draw.Draw(r.surface, r.surface.Rect, image.NewUniform(bg), image.ZP, draw.Src)
+}
+
+// Present does nothing, the surface is always up to date
+func (r *Renderer) Present() {}
+
+// FillRect draws a solid colored rectangle
+func (r *Renderer) FillRect(rect render.Rect, col color.Color) {
+	draw.Draw(r.target(), r.pixelRect(rect), image.NewUniform(col), image.ZP, draw.Over)
+}
+
+// DrawTexture draws a texture stretched over a rectangle
+func (r *Renderer) DrawTexture(tex render.Texture, rect render.Rect) {
+	img := tex.(*Texture).img
+	xdraw.BiLinear.Scale(r.target(), r.pixelRect(rect), img, img.Rect, xdraw.Over, nil)
+}
+
+// DrawText draws text starting from the top-left corner of a rectangle
+func (r *Renderer) DrawText(txt render.Text, rect render.Rect) {
+	text := txt.(*Text)
+	if text.color == nil {
+		return
+	}
+	if text.layout == nil {
+		return
+	}
+	surface := r.target()
+	origin := rect.Pixels(r.Size())
+	scale := float32(text.size) / float32(text.font.Size)
+	for _, line := range text.layout.Lines {
+		for _, glyph := range text.layout.Glyphs[line.Start:line.End] {
+			if glyph.Atlas.Width <= 0 {
+				continue
+			}
+			span := text.layout.Spans[glyph.Span]
+			dst := font.Rect{
+				X:      origin.X + glyph.Bounds.X*scale,
+				Y:      origin.Y + glyph.Bounds.Y*scale,
+				Width:  glyph.Bounds.Width * scale,
+				Height: glyph.Bounds.Height * scale,
+			}
+			style := glyphStyle{baseline: origin.Y + line.Baseline*scale}
+			if span.Bold {
+				style.weight = font.BoldWeight
+			}
+			if span.Italic {
+				style.slant = font.ItalicSlant
+			}
+			drawSDF(surface, dst, span.Font.Pages[glyph.Page].Image, glyph.Atlas, text.spanColor(span), style)
+		}
+	}
+
+	// Underlines and strikethroughs are plain rectangles
+	for _, decoration := range text.layout.Decorations {
+		bounds := decoration.Bounds
+		dst := image.Rect(
+			round(origin.X+bounds.X*scale), round(origin.Y+bounds.Y*scale),
+			round(origin.X+(bounds.X+bounds.Width)*scale), round(origin.Y+(bounds.Y+bounds.Height)*scale))
+		if dst.Dy() < 1 {
+			dst.Max.Y = dst.Min.Y + 1
+		}
+		draw.Draw(surface, dst, image.NewUniform(text.spanColor(text.layout.Spans[decoration.Span])), image.ZP, draw.Over)
+	}
+}
+
+// PushClip restricts drawing to a rectangle, intersected with the current clipping one
+func (r *Renderer) PushClip(rect render.Rect) {
+	r.PushRoundedClip(rect, 0)
+}
+
+// PushRoundedClip restricts drawing to a rounded rectangle. Drawing functions only clip to
+// its bounding box, the corners are masked out when it's popped.
+func (r *Renderer) PushRoundedClip(rect render.Rect, radius float32) {
+	area := clipArea{
+		rect:   r.pixelRect(rect).Intersect(r.clip()),
+		shape:  rect.Pixels(r.Size()),
+		radius: radius,
+	}
+	if radius > 0 {
+		area.saved = image.NewRGBA(area.rect)
+		draw.Draw(area.saved, area.rect, r.surface, area.rect.Min, draw.Src)
+	}
+	r.clips = append(r.clips, area)
+}
+
+// PopClip goes back to the clipping area before the last PushClip or PushRoundedClip
+func (r *Renderer) PopClip() {
+	if len(r.clips) == 0 {
+		return
+	}
+	area := r.clips[len(r.clips)-1]
+	r.clips = r.clips[:len(r.clips)-1]
+	if area.saved != nil {
+		maskCorners(r.surface, area)
+	}
+}
+
+// clip returns the area drawing is restricted to
+func (r *Renderer) clip() image.Rectangle {
+	if len(r.clips) == 0 {
+		return r.surface.Rect
+	}
+	return r.clips[len(r.clips)-1].rect
+}
+
+// target returns the part of the surface that can be drawn on, drawing functions
+// clip to it by themselves
+func (r *Renderer) target() *image.RGBA {
+	return r.surface.SubImage(r.clip()).(*image.RGBA)
+}
+
+// MakeTexture wraps an image so it can be drawn
+func (r *Renderer) MakeTexture(img *image.RGBA) render.Texture {
+	return &Texture{img}
+}
+
+// MakeText creates an empty text for a font
+func (r *Renderer) MakeText(fnt *font.Font) render.Text {
+	return &Text{
+		font:  fnt,
+		size:  float64(fnt.Size),
+		color: color.White,
+	}
+}
+
+// pixelRect converts a relative rectangle to the pixels it covers
+func (r *Renderer) pixelRect(rect render.Rect) image.Rectangle {
+	px := rect.Pixels(r.Size())
+	return image.Rect(round(px.X), round(px.Y), round(px.X+px.Width), round(px.Y+px.Height))
+}
+
+// Texture is an image ready to be drawn by the software renderer
+type Texture struct {
+	img *image.RGBA
+}
+
+// Size returns the image size
+func (t *Texture) Size() image.Point {
+	return t.img.Rect.Size()
+}
+
+// Text is a string laid out for the software renderer
+type Text struct {
+	font   *font.Font
+	layout *font.TextLayout
+	size   float64
+	color  color.Color
+}
+
+// SetLayout replaces the glyphs to draw
+func (t *Text) SetLayout(layout *font.TextLayout) {
+	t.layout = layout
+}
+
+// SetColor sets the text color, for spans that don't have their own
+func (t *Text) SetColor(col color.Color) {
+	t.color = col
+}
+
+// spanColor returns the color of a span, which is the text color unless the span has its own
+func (t *Text) spanColor(span font.Span) color.Color {
+	if span.Color != nil {
+		return span.Color
+	}
+	return t.color
+}
+
+// SetSize sets the font size in pixels
+func (t *Text) SetSize(size float64) {
+	t.size = size
+}
+
+// Destroy does nothing, software texts only hold memory
+func (t *Text) Destroy() {}
+
+func round(x float32) int {
+	if x < 0 {
+		return int(x - 0.5)
+	}
+	return int(x + 0.5)
+}
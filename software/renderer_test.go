@@ -0,0 +1,75 @@
+package software
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hamcha/youi/components/builtin"
+	"github.com/hamcha/youi/render"
+	"github.com/hamcha/youi/utils"
+)
+
+func checkPixel(t *testing.T, img *image.RGBA, x, y int, expected color.RGBA) {
+	if got := img.RGBAAt(x, y); got != expected {
+		t.Errorf("pixel (%d, %d): expected %v, got %v", x, y, expected, got)
+	}
+}
+
+func TestFillRect(t *testing.T) {
+	r := MakeRenderer(10, 10, utils.HexColor(0x000000ff))
+	r.Clear()
+	r.FillRect(render.Rect{X: 0.5, Y: 0, Width: 0.5, Height: 0.5}, utils.HexColor(0xff0000ff))
+
+	img := r.Image()
+	checkPixel(t, img, 2, 2, color.RGBA{0, 0, 0, 0xff})
+	checkPixel(t, img, 7, 2, color.RGBA{0xff, 0, 0, 0xff})
+	checkPixel(t, img, 7, 7, color.RGBA{0, 0, 0, 0xff})
+}
+
+func TestDrawTree(t *testing.T) {
+	r := MakeRenderer(100, 100, utils.HexColor(0x000000ff))
+
+	// Solid green 1x1 image, stretched over a canvas
+	src := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	src.SetRGBA(0, 0, color.RGBA{0, 0xff, 0, 0xff})
+	img := &builtin.Image{}
+	img.SetImage(src)
+
+	canvas := &builtin.Canvas{}
+	canvas.SetRect(image.Rect(10, 20, 30, 40))
+	canvas.AppendChild(img)
+
+	root := &builtin.Page{}
+	root.SetSize(r.Size())
+	root.AppendChild(canvas)
+
+	r.Clear()
+	root.Draw(r)
+
+	out := r.Image()
+	checkPixel(t, out, 5, 5, color.RGBA{0, 0, 0, 0xff})
+	checkPixel(t, out, 15, 25, color.RGBA{0, 0xff, 0, 0xff})
+	checkPixel(t, out, 35, 25, color.RGBA{0, 0, 0, 0xff})
+}
+
+func TestClip(t *testing.T) {
+	r := MakeRenderer(20, 20, utils.HexColor(0x000000ff))
+	r.Clear()
+
+	// Nested clips intersect, rounded ones don't paint their corners
+	r.PushClip(render.Rect{X: 0, Y: 0, Width: 0.5, Height: 1})
+	r.PushRoundedClip(render.Rect{X: 0, Y: 0, Width: 1, Height: 1}, 8)
+	r.FillRect(render.Rect{X: 0, Y: 0, Width: 1, Height: 1}, utils.HexColor(0xff0000ff))
+	r.PopClip()
+	r.PopClip()
+
+	img := r.Image()
+	checkPixel(t, img, 0, 0, color.RGBA{0, 0, 0, 0xff})
+	checkPixel(t, img, 5, 10, color.RGBA{0xff, 0, 0, 0xff})
+	checkPixel(t, img, 15, 10, color.RGBA{0, 0, 0, 0xff})
+
+	// Once popped, drawing is not clipped anymore
+	r.FillRect(render.Rect{X: 0, Y: 0, Width: 1, Height: 1}, utils.HexColor(0x00ff00ff))
+	checkPixel(t, img, 0, 0, color.RGBA{0, 0xff, 0, 0xff})
+}
@@ -0,0 +1,118 @@
+package software
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hamcha/youi/font"
+)
+
+// Same thresholds as the OpenGL font shader
+const sdfTolerance = 0.05
+
+// glyphStyle holds the synthesized styles of a glyph
+type glyphStyle struct {
+	// weight is added to the distance, to make glyphs bolder
+	weight float32
+	// slant pushes each row right by how far above baseline (in pixels) it is
+	slant, baseline float32
+}
+
+// drawSDF draws a glyph from a signed distance field atlas, scaled to fit dst
+func drawSDF(surface *image.RGBA, dst font.Rect, atlas *image.RGBA, src font.Rect, col color.Color, style glyphStyle) {
+	cr, cg, cb, ca := col.RGBA()
+
+	// Slanting moves the top right and the bottom left
+	left := dst.X + (style.baseline-dst.Y-dst.Height)*style.slant
+	right := dst.X + dst.Width + (style.baseline-dst.Y)*style.slant
+	bounds := image.Rect(round(left), round(dst.Y), round(right), round(dst.Y+dst.Height)).Intersect(surface.Rect)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		shift := (style.baseline - float32(y) - 0.5) * style.slant
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Sample the atlas at the pixel center
+			u := src.X + (float32(x)+0.5-shift-dst.X)/dst.Width*src.Width
+			v := src.Y + (float32(y)+0.5-dst.Y)/dst.Height*src.Height
+			if u < src.X || u >= src.X+src.Width {
+				continue
+			}
+			distance := sampleAlpha(atlas, u, v) + style.weight
+
+			w1 := smoothstep(0.5-sdfTolerance, 0.5+sdfTolerance, distance)
+			w2 := smoothstep(0.5-sdfTolerance*2, 0.5+sdfTolerance*2, distance)
+			alpha := (w1 + w2) / 2
+			if alpha <= 0 {
+				continue
+			}
+
+			blend(surface, x, y, cr, cg, cb, ca, alpha)
+		}
+	}
+}
+
+// sampleAlpha bilinearly samples the alpha channel of an image, returning a value within [0, 1]
+func sampleAlpha(img *image.RGBA, u, v float32) float32 {
+	u, v = u-0.5, v-0.5
+	x0, y0 := int(floor(u)), int(floor(v))
+	fx, fy := u-float32(x0), v-float32(y0)
+
+	a00 := alphaAt(img, x0, y0)
+	a10 := alphaAt(img, x0+1, y0)
+	a01 := alphaAt(img, x0, y0+1)
+	a11 := alphaAt(img, x0+1, y0+1)
+
+	top := a00 + (a10-a00)*fx
+	bottom := a01 + (a11-a01)*fx
+	return top + (bottom-top)*fy
+}
+
+func alphaAt(img *image.RGBA, x, y int) float32 {
+	// Clamp to edge, like the OpenGL texture
+	b := img.Rect
+	if x < b.Min.X {
+		x = b.Min.X
+	} else if x >= b.Max.X {
+		x = b.Max.X - 1
+	}
+	if y < b.Min.Y {
+		y = b.Min.Y
+	} else if y >= b.Max.Y {
+		y = b.Max.Y - 1
+	}
+	return float32(img.Pix[img.PixOffset(x, y)+3]) / 0xff
+}
+
+// blend draws a premultiplied color (as returned by color.Color.RGBA) over a pixel, with
+// its alpha further scaled by coverage
+func blend(surface *image.RGBA, x, y int, r, g, b, a uint32, coverage float32) {
+	cov := uint32(coverage * 0xffff)
+	r, g, b, a = r*cov/0xffff, g*cov/0xffff, b*cov/0xffff, a*cov/0xffff
+	if a == 0 {
+		return
+	}
+
+	inv := 0xffff - a
+	i := surface.PixOffset(x, y)
+	pix := surface.Pix[i : i+4 : i+4]
+	pix[0] = uint8((uint32(pix[0])*0x101*inv/0xffff + r) >> 8)
+	pix[1] = uint8((uint32(pix[1])*0x101*inv/0xffff + g) >> 8)
+	pix[2] = uint8((uint32(pix[2])*0x101*inv/0xffff + b) >> 8)
+	pix[3] = uint8((uint32(pix[3])*0x101*inv/0xffff + a) >> 8)
+}
+
+func smoothstep(edge0, edge1, x float32) float32 {
+	t := (x - edge0) / (edge1 - edge0)
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return t * t * (3 - 2*t)
+}
+
+func floor(x float32) float32 {
+	i := float32(int(x))
+	if i > x {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,237 @@
+package youi
+
+import (
+	"io"
+	"strings"
+
+	"github.com/kataras/go-errors"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/components/builtin"
+	"github.com/hamcha/youi/yuml"
+)
+
+// Style errors
+var (
+	ErrInvalidStylesheet = errors.New("Stylesheets must have <Styles> as root")
+	ErrInvalidStyle      = errors.New("<Styles> can only contain <Style> elements, got <%s>")
+	ErrStyleNoTarget     = errors.New("Style must have a Name, a TargetType or both")
+	ErrUnknownStyle      = errors.New("Unknown style \"%s\"")
+	ErrStyleTargetType   = errors.New("Style \"%s\" is for <%s> elements, not <%s>")
+	ErrStyleCycle        = errors.New("Style \"%s\" is based on itself")
+)
+
+// Style is a set of attributes given to elements, either the ones that ask for it by
+// name (Style="name") or all the ones of its TargetType
+type Style struct {
+	// Name is what elements use to pick the style, styles without one are used by all the
+	// elements of their TargetType
+	Name string
+	// TargetType is the name of the elements the style is for (like Button), empty for
+	// named styles that can be used by any element
+	TargetType string
+	// BasedOn is the name of a style whose attributes this one starts from
+	BasedOn string
+	// Attributes are the attributes set by the style, elements can override them
+	Attributes components.AttributeList
+}
+
+// Stylesheet is a collection of styles, written in YUML as:
+//
+//	<Styles xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+//		<Style TargetType="Label" FontSize="14" />
+//		<Style Name="title" BasedOn="..." FontSize="24" />
+//	</Styles>
+//
+// Styles can also be declared inside any element of a document, and are then used by
+// that element and everything inside it.
+type Stylesheet struct {
+	named map[string]*Style
+	types map[string]*Style
+}
+
+// MakeStylesheet creates an empty stylesheet
+func MakeStylesheet() *Stylesheet {
+	return &Stylesheet{
+		named: make(map[string]*Style),
+		types: make(map[string]*Style),
+	}
+}
+
+// ParseStylesheet reads a stylesheet written in YUML
+func ParseStylesheet(reader io.Reader) (*Stylesheet, error) {
+	element, err := yuml.ParseYUML(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !isStylesElement(element) {
+		return nil, yuml.ElementError(element, ErrInvalidStylesheet)
+	}
+	return parseStyles(element)
+}
+
+// Add adds a style to the stylesheet, replacing any with the same Name or TargetType
+func (s *Stylesheet) Add(style *Style) error {
+	switch {
+	case style.Name != "":
+		s.named[style.Name] = style
+	case style.TargetType != "":
+		s.types[style.TargetType] = style
+	default:
+		return ErrStyleNoTarget
+	}
+	return nil
+}
+
+// Style returns the style with a name
+func (s *Stylesheet) Style(name string) (*Style, bool) {
+	style, ok := s.named[name]
+	return style, ok
+}
+
+// TypeStyle returns the style used by all elements with a name (like Button)
+func (s *Stylesheet) TypeStyle(elementName string) (*Style, bool) {
+	style, ok := s.types[elementName]
+	return style, ok
+}
+
+func isStylesElement(element *yuml.Element) bool {
+	return element.Name.Space == builtin.Namespace && element.Name.Local == "Styles"
+}
+
+func parseStyles(element *yuml.Element) (*Stylesheet, error) {
+	sheet := MakeStylesheet()
+	for _, child := range element.Children {
+		if child.Element.Name.Local != "Style" {
+			return nil, yuml.ElementError(child.Element, ErrInvalidStyle.Format(child.Element.Name.Local))
+		}
+		attributes := toAttributeList(child.Element.Attributes)
+		style := &Style{
+			Name:       attributes.Get("Name", "").String(),
+			TargetType: attributes.Get("TargetType", "").String(),
+			BasedOn:    attributes.Get("BasedOn", "").String(),
+			Attributes: make(components.AttributeList),
+		}
+		for name, value := range attributes {
+			switch name {
+			case "Name", "TargetType", "BasedOn":
+			default:
+				style.Attributes[name] = value
+			}
+		}
+		if err := sheet.Add(style); err != nil {
+			return nil, yuml.ElementError(child.Element, err)
+		}
+	}
+	return sheet, nil
+}
+
+// splitStyles takes the <Styles> children out of an element, returning a copy without them
+func splitStyles(element *yuml.Element) (*yuml.Element, []*yuml.Element) {
+	var styles []*yuml.Element
+	for _, child := range element.Children {
+		if isStylesElement(child.Element) {
+			styles = append(styles, child.Element)
+		}
+	}
+	if len(styles) == 0 {
+		return element, nil
+	}
+
+	// Text around removed children gets joined
+	out := *element
+	out.Children = nil
+	out.Text = []string{element.Text[0]}
+	for i, child := range element.Children {
+		if isStylesElement(child.Element) {
+			out.Text[len(out.Text)-1] += element.Text[i+1]
+			continue
+		}
+		out.Children = append(out.Children, child)
+		out.Text = append(out.Text, element.Text[i+1])
+	}
+	return &out, styles
+}
+
+// pushStyles adds the styles declared inside an element to the builder's scope, returning
+// how many stylesheets were added (which must be popped even if there is an error)
+func (b *yumlBuilder) pushStyles(declared []*yuml.Element) (int, error) {
+	for i, element := range declared {
+		sheet, err := parseStyles(element)
+		if err != nil {
+			return i, err
+		}
+		b.styles = append(b.styles, sheet)
+	}
+	return len(declared), nil
+}
+
+func (b *yumlBuilder) popStyles(count int) {
+	b.styles = b.styles[:len(b.styles)-count]
+}
+
+// findStyle looks for a named style, starting from the innermost scope
+func (b *yumlBuilder) findStyle(name string) (*Style, bool) {
+	for i := len(b.styles) - 1; i >= 0; i-- {
+		if style, ok := b.styles[i].Style(name); ok {
+			return style, true
+		}
+	}
+	return nil, false
+}
+
+// applyStyles returns an element's attributes with the ones from its styles added. Type
+// styles come first (outer scopes before inner ones), then the styles named in the Style
+// attribute in order, then the element's own attributes, with later ones overriding.
+func (b *yumlBuilder) applyStyles(elementName string, attributes components.AttributeList) (components.AttributeList, error) {
+	out := make(components.AttributeList)
+	for _, sheet := range b.styles {
+		if style, ok := sheet.TypeStyle(elementName); ok {
+			if err := b.mergeStyle(out, style, elementName, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, name := range strings.Fields(attributes.Get("Style", "").String()) {
+		style, ok := b.findStyle(name)
+		if !ok {
+			return nil, ErrUnknownStyle.Format(name)
+		}
+		if err := b.mergeStyle(out, style, elementName, nil); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range attributes {
+		if name != "Style" {
+			out[name] = value
+		}
+	}
+	return b.theme.resolve(out)
+}
+
+// mergeStyle copies a style's attributes, after the ones of the styles it's based on
+func (b *yumlBuilder) mergeStyle(out components.AttributeList, style *Style, elementName string, seen map[*Style]bool) error {
+	if style.TargetType != "" && style.TargetType != elementName {
+		return ErrStyleTargetType.Format(style.Name, style.TargetType, elementName)
+	}
+	if seen[style] {
+		return ErrStyleCycle.Format(style.Name)
+	}
+	if style.BasedOn != "" {
+		base, ok := b.findStyle(style.BasedOn)
+		if !ok {
+			return ErrUnknownStyle.Format(style.BasedOn)
+		}
+		if seen == nil {
+			seen = make(map[*Style]bool)
+		}
+		seen[style] = true
+		if err := b.mergeStyle(out, base, elementName, seen); err != nil {
+			return err
+		}
+	}
+	for name, value := range style.Attributes {
+		out[name] = value
+	}
+	return nil
+}
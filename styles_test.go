@@ -0,0 +1,135 @@
+package youi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hamcha/youi/components/builtin"
+	"github.com/hamcha/youi/utils"
+	"github.com/hamcha/youi/yuml"
+)
+
+// findLabel returns the label with an Id, failing the test if there is none
+func findLabel(t *testing.T, form *Form, id string) *builtin.Label {
+	label, ok := form.FindByID(id).(*builtin.Label)
+	if !ok {
+		t.Fatalf("No label with Id %s", id)
+	}
+	return label
+}
+
+// loadError loads a document that must have a single error, and returns it
+func loadError(t *testing.T, form *Form, src string) error {
+	err := form.LoadYUML(strings.NewReader(src))
+	list, ok := err.(yuml.ErrorList)
+	if !ok || len(list) != 1 {
+		t.Fatalf("Expected a single error, got %v", err)
+	}
+	return list[0].Err
+}
+
+func TestStylePrecedence(t *testing.T) {
+	sheet, err := ParseStylesheet(strings.NewReader(`<Styles xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Style TargetType="Label" FontSize="10" Color="#111111" Wrap="true" />
+	<Style Name="title" FontSize="20" Color="#222222" />
+</Styles>`))
+	if err != nil {
+		t.Fatalf("Could not parse stylesheet: %s", err.Error())
+	}
+
+	form := makeTestForm()
+	form.AddStylesheet(sheet)
+	err = form.LoadYUML(strings.NewReader(`<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Label Id="plain" Text="Plain" />
+	<Label Id="title" Text="Title" Style="title" Color="#333333" />
+</Page>`))
+	if err != nil {
+		t.Fatalf("Could not load YUML: %s", err.Error())
+	}
+
+	// Type style, then named style, then the element's own attributes
+	plain, title := findLabel(t, form, "plain"), findLabel(t, form, "title")
+	if plain.FontSize() != 10 || utils.ToHexColor(plain.Color()) != 0x111111ff || !plain.Wrap() {
+		t.Errorf("Expected plain label to only use the type style, got size %g, color %s", plain.FontSize(), utils.ToHexColor(plain.Color()))
+	}
+	if title.FontSize() != 20 || utils.ToHexColor(title.Color()) != 0x333333ff || !title.Wrap() {
+		t.Errorf("Expected title label to use all styles, got size %g, color %s", title.FontSize(), utils.ToHexColor(title.Color()))
+	}
+}
+
+func TestStyleBasedOn(t *testing.T) {
+	form := makeTestForm()
+	err := form.LoadYUML(strings.NewReader(`<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Styles>
+		<Style Name="base" FontSize="12" Color="#111111" />
+		<Style Name="accent" BasedOn="base" Color="#222222" />
+		<Style Name="wrapped" BasedOn="accent" Wrap="true" />
+	</Styles>
+	<Label Id="label" Text="Label" Style="wrapped" />
+</Page>`))
+	if err != nil {
+		t.Fatalf("Could not load YUML: %s", err.Error())
+	}
+
+	label := findLabel(t, form, "label")
+	if label.FontSize() != 12 || utils.ToHexColor(label.Color()) != 0x222222ff || !label.Wrap() {
+		t.Errorf("Expected attributes from the whole chain, got size %g, color %s, wrap %t", label.FontSize(), utils.ToHexColor(label.Color()), label.Wrap())
+	}
+}
+
+func TestStyleErrors(t *testing.T) {
+	form := makeTestForm()
+	err := loadError(t, form, `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Styles>
+		<Style Name="first" BasedOn="second" FontSize="12" />
+		<Style Name="second" BasedOn="first" Wrap="true" />
+	</Styles>
+	<Label Text="Label" Style="first" />
+</Page>`)
+	if expected := ErrStyleCycle.Format("first").Error(); err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+
+	err = loadError(t, form, `<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Label Text="Label" Color="{Theme Accent}" />
+</Page>`)
+	if expected := ErrUnknownThemeResource.Format("Accent", "Color").Error(); err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	light := MakeTheme("Light")
+	light.Resources["Accent"] = "#3060ff"
+	dark, err := ParseTheme(strings.NewReader(`<Theme xmlns="https://yuml.ovo.ovh/schema/components/1.0" Name="Dark" Accent="#ff6030">
+	<Style TargetType="Label" FontSize="30" />
+</Theme>`))
+	if err != nil {
+		t.Fatalf("Could not parse theme: %s", err.Error())
+	}
+
+	form := makeTestForm()
+	if err := form.SetTheme(light); err != nil {
+		t.Fatalf("Could not set theme: %s", err.Error())
+	}
+	err = form.LoadYUML(strings.NewReader(`<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Label Id="label" Text="Label" Color="{Theme Accent}" />
+	<TextBox Id="name" />
+</Page>`))
+	if err != nil {
+		t.Fatalf("Could not load YUML: %s", err.Error())
+	}
+	form.FindByID("name").(*builtin.TextBox).SetValue("typed")
+
+	// Changing theme makes the document again, keeping what was typed
+	if err := form.SetTheme(dark); err != nil {
+		t.Fatalf("Could not set theme: %s", err.Error())
+	}
+	label := findLabel(t, form, "label")
+	if utils.ToHexColor(label.Color()) != 0xff6030ff || label.FontSize() != 30 {
+		t.Errorf("Expected label to use the dark theme, got size %g, color %s", label.FontSize(), utils.ToHexColor(label.Color()))
+	}
+	if value := form.FindByID("name").(*builtin.TextBox).Value(); value != "typed" {
+		t.Errorf("Expected text box value to be kept, got %q", value)
+	}
+}
@@ -0,0 +1,109 @@
+package youi
+
+import (
+	"io"
+	"strings"
+
+	"github.com/kataras/go-errors"
+
+	"github.com/hamcha/youi/components"
+	"github.com/hamcha/youi/components/builtin"
+	"github.com/hamcha/youi/yuml"
+)
+
+// Theme errors
+var (
+	ErrInvalidTheme         = errors.New("Themes must have <Theme> as root")
+	ErrUnknownThemeResource = errors.New("Unknown theme resource \"%s\" for attribute %s")
+)
+
+// Theme is a set of resources and styles used by every document loaded in a form, such as
+// a light or dark appearance. Attributes refer to resources with {Theme Name}, like
+// Color="{Theme Accent}". In YUML, themes are written as:
+//
+//	<Theme xmlns="https://yuml.ovo.ovh/schema/components/1.0" Name="Dark" Accent="#3060ff">
+//		<Style TargetType="Button" Color="{Theme Accent}" />
+//	</Theme>
+//
+// where attributes other than Name are resources and children are styles.
+type Theme struct {
+	Name      string
+	Resources map[string]string
+	// Styles are used before any stylesheet added to the form or declared in documents
+	Styles *Stylesheet
+}
+
+// MakeTheme creates an empty theme
+func MakeTheme(name string) *Theme {
+	return &Theme{
+		Name:      name,
+		Resources: make(map[string]string),
+		Styles:    MakeStylesheet(),
+	}
+}
+
+// ParseTheme reads a theme written in YUML
+func ParseTheme(reader io.Reader) (*Theme, error) {
+	element, err := yuml.ParseYUML(reader)
+	if err != nil {
+		return nil, err
+	}
+	if element.Name.Space != builtin.Namespace || element.Name.Local != "Theme" {
+		return nil, yuml.ElementError(element, ErrInvalidTheme)
+	}
+	theme := MakeTheme("")
+	for name, value := range toAttributeList(element.Attributes) {
+		if name == "Name" {
+			theme.Name = value.String()
+			continue
+		}
+		theme.Resources[name] = value.String()
+	}
+	if theme.Styles, err = parseStyles(element); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// SetTheme changes the form's theme. If a document was loaded, it's made again with the
+// new theme, keeping the state of its components where possible.
+func (f *Form) SetTheme(theme *Theme) error {
+	f.theme = theme
+	if f.source == nil {
+		return nil
+	}
+	return f.load(f.source, f.sources, true)
+}
+
+// Theme returns the form's theme, nil if it has none
+func (f *Form) Theme() *Theme {
+	return f.theme
+}
+
+// resolve replaces {Theme Name} values with the theme's resources
+func (t *Theme) resolve(attributes components.AttributeList) (components.AttributeList, error) {
+	for name, value := range attributes {
+		key, ok := themeReference(value.String())
+		if !ok {
+			continue
+		}
+		if t == nil {
+			return nil, ErrUnknownThemeResource.Format(key, name)
+		}
+		resource, ok := t.Resources[key]
+		if !ok {
+			return nil, ErrUnknownThemeResource.Format(key, name)
+		}
+		attributes[name] = components.Attribute(resource)
+	}
+	return attributes, nil
+}
+
+// themeReference returns the resource name in values like {Theme Name}
+func themeReference(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "{Theme ") || !strings.HasSuffix(value, "}") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(value, "{Theme "), "}")), true
+}
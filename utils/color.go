@@ -1,5 +1,13 @@
 package utils
 
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
 // HexColor is a single 8bpc color from a single hex number (similar to CSS)
 type HexColor uint32
 
@@ -12,3 +20,47 @@ func (hcol HexColor) RGBA() (r, g, b, a uint32) {
 	a = (h32 & 0xff) << 8
 	return
 }
+
+// ErrInvalidHexColor means a string is not a valid hex color
+var ErrInvalidHexColor = errors.New("colors must be written as #RGB, #RRGGBB or #RRGGBBAA")
+
+// ParseHexColor parses a CSS-like hex color (#RGB, #RRGGBB or #RRGGBBAA)
+func ParseHexColor(str string) (HexColor, error) {
+	if !strings.HasPrefix(str, "#") {
+		return 0, ErrInvalidHexColor
+	}
+	str = str[1:]
+
+	// Expand short form
+	if len(str) == 3 {
+		str = string([]byte{str[0], str[0], str[1], str[1], str[2], str[2]})
+	}
+
+	// Add alpha if missing
+	if len(str) == 6 {
+		str += "ff"
+	}
+
+	if len(str) != 8 {
+		return 0, ErrInvalidHexColor
+	}
+
+	val, err := strconv.ParseUint(str, 16, 32)
+	if err != nil {
+		return 0, ErrInvalidHexColor
+	}
+	return HexColor(val), nil
+}
+
+// ToHexColor converts any color to a HexColor
+func ToHexColor(col color.Color) HexColor {
+	if hcol, ok := col.(HexColor); ok {
+		return hcol
+	}
+	nrgba := color.NRGBAModel.Convert(col).(color.NRGBA)
+	return HexColor(uint32(nrgba.R)<<24 | uint32(nrgba.G)<<16 | uint32(nrgba.B)<<8 | uint32(nrgba.A))
+}
+
+func (hcol HexColor) String() string {
+	return fmt.Sprintf("#%08x", uint32(hcol))
+}
@@ -0,0 +1,104 @@
+package yuml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Position is where something is in a YUML document
+type Position struct {
+	// File is the name of the document, if it has one
+	File string
+	// Line and Column start from 1, they are 0 when unknown
+	Line, Column int
+}
+
+func (p Position) String() string {
+	var parts []string
+	if p.File != "" {
+		parts = append(parts, p.File)
+	}
+	if p.Line > 0 {
+		parts = append(parts, fmt.Sprint(p.Line))
+		if p.Column > 0 {
+			parts = append(parts, fmt.Sprint(p.Column))
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// Error is an error about a part of a YUML document, like:
+//
+//	form.yuml:12:3: Page/Stack/Label[2]: FontSize must be a number
+type Error struct {
+	Position Position
+	// Path is where the element is in the tree, see Element.Path
+	Path string
+	Err  error
+}
+
+// ElementError returns an error about an element, with its position and path. Errors that
+// already have a position are returned as they are.
+func ElementError(element *Element, err error) *Error {
+	if yumlErr, ok := err.(*Error); ok {
+		return yumlErr
+	}
+	return &Error{Position: element.Position, Path: element.Path, Err: err}
+}
+
+func (e *Error) Error() string {
+	var parts []string
+	if position := e.Position.String(); position != "" {
+		parts = append(parts, position)
+	}
+	if e.Path != "" {
+		parts = append(parts, e.Path)
+	}
+	return strings.Join(append(parts, e.Err.Error()), ": ")
+}
+
+// Unwrap returns the error without position
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is a list of errors found in a single pass over a document
+type ErrorList []*Error
+
+// Add adds an error about an element to the list, see ElementError
+func (l *ErrorList) Add(element *Element, err error) {
+	if list, ok := err.(ErrorList); ok {
+		*l = append(*l, list...)
+		return
+	}
+	*l = append(*l, ElementError(element, err))
+}
+
+// Err returns the list as an error, or nil if it's empty
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+func (l ErrorList) Error() string {
+	lines := make([]string, len(l))
+	for i, err := range l {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// syntaxError adds a position to errors from the XML decoder
+func syntaxError(err error, position Position, path string) error {
+	if syntaxErr, ok := err.(*xml.SyntaxError); ok {
+		if position.Line != syntaxErr.Line {
+			// The decoder doesn't say where in the line, only which line
+			position.Line, position.Column = syntaxErr.Line, 0
+		}
+		return &Error{Position: position, Path: path, Err: fmt.Errorf("XML syntax error: %s", syntaxErr.Msg)}
+	}
+	return &Error{Position: position, Path: path, Err: err}
+}
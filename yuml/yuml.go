@@ -15,11 +15,24 @@ type Element struct {
 	Attributes Attributes
 	Children   []Child
 	Content    []byte
+
+	// Text holds the character data around children, Text[i] is what comes right before
+	// Children[i] and the last item is what comes after all of them
+	Text []string
+
+	// Position is where the element starts in the document
+	Position Position
+	// Path is where the element is in the tree, like Page/Stack/Label[2] for the second
+	// Label of a Stack
+	Path string
 }
 
 // Child contains a YUML element and its parent-related attributes
 type Child struct {
-	Element  *Element
+	Element *Element
+
+	// Settings are the attributes meant for the parent element, written as
+	// Parent.Name="value" on the child. The "Parent." prefix is removed.
 	Settings []xml.Attr
 }
 
@@ -33,33 +46,56 @@ var (
 
 // ParseYUML tries to read YUML code and parse it as such, returning the root element
 func ParseYUML(reader io.Reader) (*Element, error) {
+	return ParseYUMLFile(reader, "")
+}
+
+// ParseYUMLFile works like ParseYUML, using a file name for the positions of elements and
+// errors (see Position)
+func ParseYUMLFile(reader io.Reader, file string) (*Element, error) {
 	var scope []*Element
 	var current *Element
 
 	decoder := xml.NewDecoder(reader)
 	for {
+		// Tokens start where the last one ended
+		var position Position
+		position.File = file
+		position.Line, position.Column = decoder.InputPos()
+
 		token, err := decoder.Token()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, err
+			path := ""
+			if current != nil {
+				path = current.Path
+			}
+			return nil, syntaxError(err, position, path)
 		}
 		switch v := token.(type) {
 		case xml.StartElement:
-			//TODO Filter attributes and settings apart
 			if current != nil {
 				scope = append(scope, current)
 			}
 			current = &Element{
-				Name:       v.Name,
-				Attributes: Attributes(v.Attr),
+				Name:     v.Name,
+				Text:     []string{""},
+				Position: position,
+				Path:     v.Name.Local,
 			}
 			if len(scope) > 0 {
 				parent := scope[len(scope)-1]
+				current.Path = parent.Path + "/" + childName(parent, v.Name.Local)
+				attributes, settings := splitSettings(v.Attr, parent.Name.Local)
+				current.Attributes = attributes
 				parent.Children = append(parent.Children, Child{
-					Element: current,
+					Element:  current,
+					Settings: settings,
 				})
+				parent.Text = append(parent.Text, "")
+			} else {
+				current.Attributes = Attributes(v.Attr)
 			}
 		case xml.EndElement:
 			if len(scope) == 0 {
@@ -68,7 +104,9 @@ func ParseYUML(reader io.Reader) (*Element, error) {
 			current, scope = scope[len(scope)-1], scope[:len(scope)-1]
 		case xml.CharData:
 			if current != nil {
-				current.Content = v
+				// Text can come in multiple chunks, and the decoder reuses its buffer
+				current.Content = append(current.Content, v...)
+				current.Text[len(current.Text)-1] += string(v)
 			}
 		case xml.Comment:
 			// Ignore comments, for now
@@ -79,7 +117,43 @@ func ParseYUML(reader io.Reader) (*Element, error) {
 
 		}
 	}
-	return nil, ErrIncompleteYuml
+	var position Position
+	position.File = file
+	position.Line, position.Column = decoder.InputPos()
+	path := ""
+	if current != nil {
+		path = current.Path
+	}
+	return nil, &Error{Position: position, Path: path, Err: ErrIncompleteYuml}
+}
+
+// childName returns how a new child is called in element paths, with its index if it's
+// not the first one with its name
+func childName(parent *Element, name string) string {
+	count := 1
+	for _, child := range parent.Children {
+		if child.Element.Name.Local == name {
+			count++
+		}
+	}
+	if count == 1 {
+		return name
+	}
+	return fmt.Sprintf("%s[%d]", name, count)
+}
+
+// splitSettings separates an element's own attributes from the ones meant for its parent
+func splitSettings(attrs []xml.Attr, parentName string) (attributes Attributes, settings []xml.Attr) {
+	prefix := parentName + "."
+	for _, attr := range attrs {
+		if attr.Name.Space == "" && strings.HasPrefix(attr.Name.Local, prefix) {
+			attr.Name.Local = strings.TrimPrefix(attr.Name.Local, prefix)
+			settings = append(settings, attr)
+			continue
+		}
+		attributes = append(attributes, attr)
+	}
+	return
 }
 
 func (y Element) String() string {
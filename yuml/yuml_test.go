@@ -14,7 +14,7 @@ func TestParseYUMLSimple(t *testing.T) {
 	</Canvas>
 </Page>
 `
-	out, err := parseYUML(strings.NewReader(simple))
+	out, err := ParseYUML(strings.NewReader(simple))
 	if err != nil {
 		t.Error(err)
 		return
@@ -31,3 +31,84 @@ func TestParseYUMLSimple(t *testing.T) {
 		return
 	}
 }
+
+func TestParseYUMLSettings(t *testing.T) {
+	const src = `
+<Page xmlns="https://yuml.ovo.ovh/schema/components/1.0">
+	<Stack Direction="Row">
+		<Image Path="out.png" Stack.Grow="1" Canvas.X="10" />
+	</Stack>
+</Page>
+`
+	out, err := ParseYUML(strings.NewReader(src))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	child := out.Children[0].Element.Children[0]
+	if len(child.Settings) != 1 || child.Settings[0].Name.Local != "Grow" || child.Settings[0].Value != "1" {
+		t.Errorf("expected a single Grow=1 setting, got %v", child.Settings)
+	}
+
+	// Settings for other elements are left alone
+	if len(child.Element.Attributes) != 2 || child.Element.Attributes[1].Name.Local != "Canvas.X" {
+		t.Errorf("expected Path and Canvas.X attributes, got %v", child.Element.Attributes)
+	}
+}
+
+func TestParseYUMLText(t *testing.T) {
+	const src = `<Label>Hello <Bold>big</Bold> world &amp; more</Label>`
+	out, err := ParseYUML(strings.NewReader(src))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(out.Text) != 2 || out.Text[0] != "Hello " || out.Text[1] != " world & more" {
+		t.Errorf("unexpected text chunks %q", out.Text)
+	}
+	if string(out.Content) != "Hello  world & more" {
+		t.Errorf("unexpected content %q", out.Content)
+	}
+	if bold := out.Children[0].Element; len(bold.Text) != 1 || bold.Text[0] != "big" {
+		t.Errorf("unexpected child text %q", bold.Text)
+	}
+}
+
+func TestParseYUMLPositions(t *testing.T) {
+	const src = `<Page>
+	<Stack>
+		<Label />
+		<Label />
+	</Stack>
+</Page>`
+	out, err := ParseYUMLFile(strings.NewReader(src), "page.yuml")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	second := out.Children[0].Element.Children[1].Element
+	if second.Path != "Page/Stack/Label[2]" {
+		t.Errorf("unexpected path %q", second.Path)
+	}
+	if pos := second.Position.String(); pos != "page.yuml:4:3" {
+		t.Errorf("unexpected position %q", pos)
+	}
+}
+
+func TestParseYUMLErrors(t *testing.T) {
+	const src = `<Page>
+	<Stack>
+		<Label></Stack>
+</Page>`
+	_, err := ParseYUMLFile(strings.NewReader(src), "page.yuml")
+	yumlErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected a positioned error, got %v", err)
+	}
+	if yumlErr.Position.File != "page.yuml" || yumlErr.Position.Line != 3 || yumlErr.Path != "Page/Stack/Label" {
+		t.Errorf("unexpected error location: %s", yumlErr.Error())
+	}
+}